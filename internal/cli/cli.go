@@ -0,0 +1,133 @@
+// Package cli implements the non-interactive ghostconfig subcommands
+package cli
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	"github.com/otiai10/ghostconfig/internal/config"
+	"github.com/otiai10/ghostconfig/internal/i18n"
+	"github.com/otiai10/ghostconfig/internal/schema"
+)
+
+// Exit codes returned by subcommands
+const (
+	ExitOK         = 0
+	ExitError      = 1
+	ExitUsage      = 2
+	ExitNotSet     = 3 // get: the key is not set, its default was printed
+	ExitUnknownKey = 4 // the key is not a Ghostty option
+	ExitInvalid    = 5 // Ghostty rejects the value, validate found errors or fmt -check found unformatted files
+)
+
+// commands maps subcommand names to their implementations
+var commands = map[string]func(args []string) int{
+	"get":         runGet,
+	"set":         runSet,
+	"unset":       runUnset,
+	"list":        runList,
+	"validate":    runValidate,
+	"diff":        runDiff,
+	"fmt":         runFmt,
+	"history":     runHistory,
+	"restore":     runRestore,
+	"schema-diff": runSchemaDiff,
+}
+
+// Run runs the subcommand named by args[0] and returns its exit code.
+// ok is false if args does not start with a known subcommand.
+func Run(args []string) (code int, ok bool) {
+	if len(args) == 0 {
+		return 0, false
+	}
+	run, ok := commands[args[0]]
+	if !ok {
+		return 0, false
+	}
+	return run(args[1:]), true
+}
+
+// configFlags holds the flags shared by subcommands that read a config
+type configFlags struct {
+	file      *string
+	backupDir *string
+	backups   *int
+}
+
+func addConfigFlags(fs *flag.FlagSet) *configFlags {
+	return &configFlags{
+		file:      fs.String("file", "", "Path to config file (default: ~/.config/ghostty/config)"),
+		backupDir: fs.String("backup-dir", "", "Directory for config snapshots (default: $XDG_STATE_HOME/ghostconfig/backups)"),
+		backups:   fs.Int("backups", config.DefaultBackupKeep, "Number of snapshots to keep per config file (0 disables backups)"),
+	}
+}
+
+// load loads the config selected by the flags, with backups enabled
+func (f *configFlags) load() (*config.Config, error) {
+	cfg, err := config.Load(*f.file)
+	if err != nil {
+		return nil, err
+	}
+	cfg.Backups = f.store()
+	return cfg, nil
+}
+
+// store returns the backup store selected by the flags, or nil if disabled
+func (f *configFlags) store() *config.BackupStore {
+	if *f.backups <= 0 {
+		return nil
+	}
+	return config.NewBackupStore(*f.backupDir, *f.backups)
+}
+
+// loadOptions loads the Ghostty schema, see schema.Load
+func loadOptions() ([]schema.Option, error) {
+	sch, err := schema.Load(false)
+	if err != nil {
+		return nil, fmt.Errorf("%w (%s)", err, i18n.T("error.ghostty_not_found"))
+	}
+	if len(sch.Options) == 0 {
+		return nil, fmt.Errorf("%s", i18n.T("error.no_options"))
+	}
+	return sch.Options, nil
+}
+
+// findOption returns the option named key
+func findOption(options []schema.Option, key string) (schema.Option, bool) {
+	for _, opt := range options {
+		if opt.Key == key {
+			return opt, true
+		}
+	}
+	return schema.Option{}, false
+}
+
+// unknownKey reports a key that is not a Ghostty option
+func unknownKey(key string) int {
+	fmt.Fprintf(os.Stderr, i18n.T("cli.unknown_key")+"\n", key)
+	return ExitUnknownKey
+}
+
+// fail prints an error and returns ExitError
+func fail(err error) int {
+	fmt.Fprintf(os.Stderr, i18n.T("msg.error")+"\n", err)
+	return ExitError
+}
+
+// usage returns a flag.FlagSet usage function for a subcommand
+func usage(fs *flag.FlagSet, synopsis string) func() {
+	return func() {
+		fmt.Fprintf(fs.Output(), "Usage: ghostconfig %s\n", synopsis)
+		fs.PrintDefaults()
+	}
+}
+
+// flushed flushes a tabwriter and returns the exit code for its error
+func flushed(w *tabwriter.Writer) int {
+	if err := w.Flush(); err != nil {
+		return fail(err)
+	}
+	return ExitOK
+}
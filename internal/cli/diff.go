@@ -0,0 +1,190 @@
+package cli
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/otiai10/ghostconfig/internal/config"
+	"github.com/otiai10/ghostconfig/internal/i18n"
+	"github.com/otiai10/ghostconfig/internal/schema"
+)
+
+// ANSI colors for diff output
+const (
+	colorRed   = "\x1b[31m"
+	colorGreen = "\x1b[32m"
+	colorBold  = "\x1b[1m"
+	colorReset = "\x1b[0m"
+)
+
+// ghostconfig diff [file] [file] - Compare effective values key by key.
+// Without files the config is compared to the defaults, with one file the
+// config is compared to it, and with two files they are compared.
+func runDiff(args []string) int {
+	fs := flag.NewFlagSet("diff", flag.ContinueOnError)
+	fs.Usage = usage(fs, "diff [flags] [other] | diff [flags] <from> <to>")
+	file := fs.String("file", "", "Path to config file (default: ~/.config/ghostty/config)")
+	format := fs.String("format", "unified", "Output format: unified, table or json")
+	colorMode := fs.String("color", "auto", "Color output: auto, always or never")
+	if err := fs.Parse(args); err != nil {
+		return ExitUsage
+	}
+	if fs.NArg() > 2 ||
+		(*format != "unified" && *format != "table" && *format != "json") ||
+		(*colorMode != "auto" && *colorMode != "always" && *colorMode != "never") {
+		fs.Usage()
+		return ExitUsage
+	}
+
+	options, err := loadOptions()
+	if err != nil {
+		return fail(err)
+	}
+
+	var fromName, toName string
+	var from, to map[string][]string
+	switch fs.NArg() {
+	case 0:
+		fromName, from = i18n.T("cli.defaults"), config.Defaults(options)
+		toName, to, err = loadValues(*file)
+	case 1:
+		if fromName, from, err = loadValues(*file); err == nil {
+			toName, to, err = loadValues(fs.Arg(0))
+		}
+	case 2:
+		if fromName, from, err = loadValues(fs.Arg(0)); err == nil {
+			toName, to, err = loadValues(fs.Arg(1))
+		}
+	}
+	if err != nil {
+		return fail(err)
+	}
+
+	changes := config.Compare(from, to, options)
+	if changes == nil {
+		changes = []config.Change{}
+	}
+	color := *colorMode == "always" || (*colorMode == "auto" && isTerminal(os.Stdout))
+
+	switch *format {
+	case "json":
+		err = writeJSON(os.Stdout, changes)
+	case "table":
+		writeDiffTable(os.Stdout, changes, fromName, toName, color)
+	default:
+		writeUnifiedDiff(os.Stdout, changes, fromName, toName, color)
+	}
+	if err != nil {
+		return fail(err)
+	}
+	return ExitOK
+}
+
+// loadValues loads a config file with its includes
+func loadValues(path string) (string, map[string][]string, error) {
+	cfg, err := config.Load(path)
+	if err != nil {
+		return "", nil, err
+	}
+	if _, err := os.Stat(cfg.Path); err != nil {
+		return "", nil, err
+	}
+	return cfg.Path, cfg.Values, nil
+}
+
+// isTerminal reports whether f is a terminal rather than a pipe or file
+func isTerminal(f *os.File) bool {
+	info, err := f.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
+
+func paint(s, color string, enabled bool) string {
+	if !enabled {
+		return s
+	}
+	return color + s + colorReset
+}
+
+// writeUnifiedDiff prints changes as "-key = value" and "+key = value"
+// lines under a header per category. Values of repeatable keys found on
+// both sides are shown as context.
+func writeUnifiedDiff(w io.Writer, changes []config.Change, fromName, toName string, color bool) {
+	if len(changes) == 0 {
+		return
+	}
+	fmt.Fprintln(w, paint("--- "+fromName, colorBold, color))
+	fmt.Fprintln(w, paint("+++ "+toName, colorBold, color))
+	section := ""
+	for _, c := range changes {
+		if c.Section != section {
+			section = c.Section
+			fmt.Fprintln(w, paint("@@ "+schema.CategoryName(section)+" @@", colorBold, color))
+		}
+		for _, v := range c.From {
+			if !slices.Contains(c.To, v) {
+				fmt.Fprintln(w, paint("-"+c.Key+" = "+v, colorRed, color))
+			}
+		}
+		for _, v := range c.To {
+			if slices.Contains(c.From, v) {
+				fmt.Fprintln(w, " "+c.Key+" = "+v)
+			} else {
+				fmt.Fprintln(w, paint("+"+c.Key+" = "+v, colorGreen, color))
+			}
+		}
+	}
+}
+
+// writeDiffTable prints changes as a table with one row per key.
+// Columns are padded by hand, as color codes would upset tabwriter.
+func writeDiffTable(w io.Writer, changes []config.Change, fromName, toName string, color bool) {
+	rows := [][3]string{{"KEY", fromName, toName}}
+	sections := []string{""}
+	section := ""
+	for _, c := range changes {
+		if c.Section != section {
+			section = c.Section
+			rows = append(rows, [3]string{"[" + schema.CategoryName(section) + "]"})
+			sections = append(sections, section)
+		}
+		rows = append(rows, [3]string{c.Key, diffCell(c.From), diffCell(c.To)})
+		sections = append(sections, "")
+	}
+
+	var widths [2]int
+	for i, row := range rows {
+		if sections[i] != "" {
+			continue // category headers span the row
+		}
+		for col := range widths {
+			widths[col] = max(widths[col], utf8.RuneCountInString(row[col]))
+		}
+	}
+
+	for i, row := range rows {
+		if sections[i] != "" {
+			fmt.Fprintln(w, paint(row[0], colorBold, color))
+			continue
+		}
+		pad := func(col int) string {
+			return row[col] + strings.Repeat(" ", widths[col]-utf8.RuneCountInString(row[col])+2)
+		}
+		if i == 0 {
+			fmt.Fprintln(w, pad(0)+pad(1)+row[2])
+			continue
+		}
+		fmt.Fprintln(w, pad(0)+paint(pad(1), colorRed, color)+paint(row[2], colorGreen, color))
+	}
+}
+
+func diffCell(values []string) string {
+	if len(values) == 0 {
+		return "-"
+	}
+	return strings.Join(values, ", ")
+}
@@ -0,0 +1,39 @@
+package cli
+
+import "testing"
+
+func TestDiffOutput(t *testing.T) {
+	for _, format := range []string{"unified", "table", "json"} {
+		t.Run(format, func(t *testing.T) {
+			isolate(t)
+			code, stdout, stderr := run(t, "diff", "-format", format, "-color", "never", "testdata/diff-from.config", "testdata/diff-to.config")
+			if code != ExitOK {
+				t.Fatalf("Expected exit code %d, got %d (%s)", ExitOK, code, stderr)
+			}
+			golden(t, "diff."+format, stdout)
+		})
+	}
+
+	isolate(t)
+	_, stdout, _ := run(t, "diff", "-format", "unified", "-color", "always", "testdata/diff-from.config", "testdata/diff-to.config")
+	golden(t, "diff.color", stdout)
+	if _, stdout, _ := run(t, "diff", "testdata/diff-from.config", "testdata/diff-from.config"); stdout != "" {
+		t.Errorf("Expected no output for the same file, got %q", stdout)
+	}
+}
+
+func TestDiffExitCodes(t *testing.T) {
+	isolate(t)
+	for _, args := range [][]string{
+		{"a", "b", "c"},
+		{"-format", "html"},
+		{"-color", "rainbow"},
+	} {
+		if code, _, _ := run(t, "diff", args...); code != ExitUsage {
+			t.Errorf("Expected exit code %d for %v, got %d", ExitUsage, args, code)
+		}
+	}
+	if code, _, _ := run(t, "diff", "testdata/diff-from.config", "testdata/missing.config"); code != ExitError {
+		t.Errorf("Expected exit code %d for a missing file, got %d", ExitError, code)
+	}
+}
@@ -0,0 +1,70 @@
+package cli
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/otiai10/ghostconfig/internal/config"
+	"github.com/otiai10/ghostconfig/internal/schema"
+)
+
+// ghostconfig fmt [file...] - Rewrite config files in the canonical layout
+func runFmt(args []string) int {
+	fs := flag.NewFlagSet("fmt", flag.ContinueOnError)
+	fs.Usage = usage(fs, "fmt [flags] [file...]")
+	flags := addConfigFlags(fs)
+	check := fs.Bool("check", false, "Do not write; list files that are not formatted and fail if there are any")
+	diff := fs.Bool("diff", false, "Do not write; print a diff of the changes")
+	minimize := fs.Bool("minimize", false, "Drop entries that equal their default or are overridden later, keeping their comments")
+	if err := fs.Parse(args); err != nil {
+		return ExitUsage
+	}
+	files := fs.Args()
+	if len(files) == 0 {
+		files = []string{*flags.file}
+		if *flags.file == "" {
+			files = []string{config.DefaultPath()}
+		}
+	}
+
+	var options []schema.Option
+	if *minimize {
+		var err error
+		if options, err = loadOptions(); err != nil {
+			return fail(err)
+		}
+	}
+
+	store := flags.store()
+	code := ExitOK
+	for _, file := range files {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			return fail(err)
+		}
+		out := config.Format(data, options)
+		if bytes.Equal(out, data) {
+			continue
+		}
+
+		switch {
+		case *check:
+			fmt.Println(file)
+			code = ExitInvalid
+		case *diff:
+			fmt.Print(config.UnifiedDiff(file, file, data, out))
+		default:
+			if store != nil {
+				if err := store.Snapshot(file); err != nil {
+					return fail(err)
+				}
+			}
+			if err := config.WriteFile(file, out); err != nil {
+				return fail(err)
+			}
+		}
+	}
+	return code
+}
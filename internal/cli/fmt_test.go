@@ -0,0 +1,45 @@
+package cli
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// copyConfig copies a file from testdata to a temporary directory
+func copyConfig(t *testing.T, name string) string {
+	data, err := os.ReadFile(filepath.Join("testdata", name))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return tempConfig(t, string(data))
+}
+
+func TestFmt(t *testing.T) {
+	isolate(t)
+	path := copyConfig(t, "fmt-input.config")
+
+	if code, stdout, _ := run(t, "fmt", "-check", path); code != ExitInvalid || stdout != path+"\n" {
+		t.Errorf("Expected -check to list the file and fail, got %d %q", code, stdout)
+	}
+	if code, stdout, _ := run(t, "fmt", "-diff", path); code != ExitOK || stdout == "" {
+		t.Errorf("Expected -diff to print a diff, got %d %q", code, stdout)
+	}
+
+	if code, _, stderr := run(t, "fmt", "-backups", "0", path); code != ExitOK {
+		t.Fatalf("Expected exit code %d, got %d (%s)", ExitOK, code, stderr)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	golden(t, "fmt-output.config", string(data))
+
+	// Formatting is idempotent
+	if code, stdout, _ := run(t, "fmt", "-check", path); code != ExitOK || stdout != "" {
+		t.Errorf("Expected a formatted file to pass -check, got %d %q", code, stdout)
+	}
+	if code, _, _ := run(t, "fmt", "testdata/missing.config"); code != ExitError {
+		t.Errorf("Expected exit code %d for a missing file, got %d", ExitError, code)
+	}
+}
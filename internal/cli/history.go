@@ -0,0 +1,89 @@
+package cli
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	"github.com/otiai10/ghostconfig/internal/i18n"
+)
+
+// ghostconfig history [id] - List snapshots, or diff one against the current file
+func runHistory(args []string) int {
+	fs := flag.NewFlagSet("history", flag.ContinueOnError)
+	fs.Usage = usage(fs, "history [flags] [id]")
+	flags := addConfigFlags(fs)
+	if err := fs.Parse(args); err != nil {
+		return ExitUsage
+	}
+
+	cfg, err := flags.load()
+	if err != nil {
+		return fail(err)
+	}
+	if cfg.Backups == nil {
+		return fail(fmt.Errorf("%s", i18n.T("cli.backups_disabled")))
+	}
+
+	if fs.NArg() > 0 {
+		snap, err := cfg.Backups.Find(fs.Arg(0))
+		if err != nil {
+			return fail(err)
+		}
+		diff, err := cfg.Backups.Diff(snap)
+		if err != nil {
+			return fail(err)
+		}
+		if diff == "" {
+			fmt.Println(i18n.T("cli.identical"))
+		} else {
+			fmt.Print(diff)
+		}
+		return ExitOK
+	}
+
+	snapshots, err := cfg.Backups.List(cfg.Files...)
+	if err != nil {
+		return fail(err)
+	}
+	if len(snapshots) == 0 {
+		fmt.Println(i18n.T("cli.no_snapshots"))
+		return ExitOK
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(w, "ID\tTIME\tSIZE\tFILE")
+	for _, snap := range snapshots {
+		fmt.Fprintf(w, "%s\t%s\t%d\t%s\n", snap.ID, snap.Time.Format("2006-01-02 15:04:05"), snap.Size, snap.File)
+	}
+	return flushed(w)
+}
+
+// ghostconfig restore <id> - Restore a snapshot
+func runRestore(args []string) int {
+	fs := flag.NewFlagSet("restore", flag.ContinueOnError)
+	fs.Usage = usage(fs, "restore [flags] <id>")
+	flags := addConfigFlags(fs)
+	if err := fs.Parse(args); err != nil {
+		return ExitUsage
+	}
+	if fs.NArg() != 1 {
+		fs.Usage()
+		return ExitUsage
+	}
+
+	store := flags.store()
+	if store == nil {
+		return fail(fmt.Errorf("%s", i18n.T("cli.backups_disabled")))
+	}
+	snap, err := store.Find(fs.Arg(0))
+	if err != nil {
+		return fail(err)
+	}
+	if err := store.Restore(snap); err != nil {
+		return fail(err)
+	}
+	fmt.Printf(i18n.T("cli.restored")+"\n", snap.File, snap.ID)
+	return ExitOK
+}
@@ -0,0 +1,133 @@
+package cli
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+
+	"github.com/otiai10/ghostconfig/internal/config"
+	"github.com/otiai10/ghostconfig/internal/i18n"
+	"github.com/otiai10/ghostconfig/internal/schema"
+)
+
+// SchemaDiff is the JSON output of schema-diff
+type SchemaDiff struct {
+	From    string                `json:"from"`
+	To      string                `json:"to"`
+	Changes []schema.OptionChange `json:"changes"`
+	// Affected lists the keys set in the config that were removed or
+	// whose default changed
+	Affected []string `json:"affected"`
+}
+
+// ghostconfig schema-diff [from] [to] - Show the options added, removed or
+// given a new default between two Ghostty versions. Without versions the
+// installed Ghostty is compared to the newest older version seen.
+func runSchemaDiff(args []string) int {
+	fs := flag.NewFlagSet("schema-diff", flag.ContinueOnError)
+	fs.Usage = usage(fs, "schema-diff [flags] [from] [to]")
+	file := fs.String("file", "", "Path to config file (default: ~/.config/ghostty/config)")
+	format := fs.String("format", "text", "Output format: text or json")
+	colorMode := fs.String("color", "auto", "Color output: auto, always or never")
+	list := fs.Bool("versions", false, "List the Ghostty versions whose schema is known")
+	if err := fs.Parse(args); err != nil {
+		return ExitUsage
+	}
+	if fs.NArg() > 2 ||
+		(*format != "text" && *format != "json") ||
+		(*colorMode != "auto" && *colorMode != "always" && *colorMode != "never") {
+		fs.Usage()
+		return ExitUsage
+	}
+
+	if *list {
+		for _, v := range schema.Versions() {
+			fmt.Println(v)
+		}
+		return ExitOK
+	}
+
+	var from, to schema.Schema
+	var err error
+	if fs.NArg() == 2 {
+		to, err = schema.LoadVersion(fs.Arg(1))
+	} else {
+		to, err = schema.Load(false)
+	}
+	if err != nil {
+		return fail(err)
+	}
+	if fs.NArg() > 0 {
+		from, err = schema.LoadVersion(fs.Arg(0))
+		if err != nil {
+			return fail(err)
+		}
+	} else {
+		var ok bool
+		if from, ok = schema.Previous(to.Version); !ok {
+			fmt.Println(i18n.T("cli.schema_no_previous"))
+			return ExitOK
+		}
+	}
+
+	cfg, err := config.Load(*file)
+	if err != nil {
+		return fail(err)
+	}
+	changes := schema.Diff(from.Options, to.Options)
+	if changes == nil {
+		changes = []schema.OptionChange{}
+	}
+	diff := SchemaDiff{
+		From:     from.Version,
+		To:       to.Version,
+		Changes:  changes,
+		Affected: config.Affected(cfg.Values, changes),
+	}
+
+	if *format == "json" {
+		if err := writeJSON(os.Stdout, diff); err != nil {
+			return fail(err)
+		}
+		return ExitOK
+	}
+	color := *colorMode == "always" || (*colorMode == "auto" && isTerminal(os.Stdout))
+	writeSchemaDiff(os.Stdout, diff, cfg.Path, color)
+	return ExitOK
+}
+
+// writeSchemaDiff prints added options as "+key = default", removed ones
+// as "-key" and new defaults as "~key = old -> new" under a header per
+// category. Keys set in the config are marked with "!".
+func writeSchemaDiff(w io.Writer, diff SchemaDiff, path string, color bool) {
+	fmt.Fprintln(w, paint("--- Ghostty "+diff.From, colorBold, color))
+	fmt.Fprintln(w, paint("+++ Ghostty "+diff.To, colorBold, color))
+	if len(diff.Changes) == 0 {
+		fmt.Fprintln(w, i18n.T("cli.schema_identical"))
+		return
+	}
+	section := ""
+	for _, c := range diff.Changes {
+		if c.Section != section {
+			section = c.Section
+			fmt.Fprintln(w, paint("@@ "+schema.CategoryName(section)+" @@", colorBold, color))
+		}
+		mark := " "
+		if slices.Contains(diff.Affected, c.Key) {
+			mark = "!"
+		}
+		switch c.Kind {
+		case schema.ChangeAdded:
+			fmt.Fprintln(w, paint(mark+"+"+c.Key+" = "+c.To, colorGreen, color))
+		case schema.ChangeRemoved:
+			fmt.Fprintln(w, paint(mark+"-"+c.Key, colorRed, color))
+		default:
+			fmt.Fprintf(w, "%s~%s = %s -> %s\n", mark, c.Key, paint(c.From, colorRed, color), paint(c.To, colorGreen, color))
+		}
+	}
+	if len(diff.Affected) > 0 {
+		fmt.Fprintf(w, "\n"+i18n.T("cli.schema_affected")+"\n", len(diff.Affected), path)
+	}
+}
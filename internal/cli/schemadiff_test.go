@@ -0,0 +1,63 @@
+package cli
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// cacheVersion writes the schema of a Ghostty version to the cache
+func cacheVersion(t *testing.T, version, options string) {
+	dir := filepath.Join(os.Getenv("XDG_CACHE_HOME"), "ghostconfig", "versions")
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	entry := `{"ghostty": "Ghostty ` + version + `", "version": "` + version + `", "data": ` + options + `}`
+	if err := os.WriteFile(filepath.Join(dir, version+".json"), []byte(entry), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestSchemaDiff(t *testing.T) {
+	isolate(t)
+	cacheVersion(t, "1.0.0", `[
+		{"key": "font-size", "default": "13"},
+		{"key": "cursor-style", "default": "block"},
+		{"key": "gtk-wide-tabs", "default": "true"}
+	]`)
+	cacheVersion(t, "1.1.0", `[
+		{"key": "font-size", "default": "12"},
+		{"key": "cursor-style", "default": "block"},
+		{"key": "window-save-state", "default": "default"}
+	]`)
+	path := tempConfig(t, "font-size = 14\ngtk-wide-tabs = false\n")
+
+	code, stdout, stderr := run(t, "schema-diff", "-file", path, "-color", "never", "1.0.0", "1.1.0")
+	if code != ExitOK {
+		t.Fatalf("Expected exit code %d, got %d (%s)", ExitOK, code, stderr)
+	}
+	golden(t, "schema-diff.text", strings.ReplaceAll(stdout, path, "CONFIG"))
+
+	_, stdout, _ = run(t, "schema-diff", "-file", path, "-format", "json", "1.0.0", "1.1.0")
+	golden(t, "schema-diff.json", stdout)
+
+	_, stdout, _ = run(t, "schema-diff", "-file", path, "-color", "never", "1.1.0", "1.1.0")
+	if !strings.HasSuffix(stdout, "+++ Ghostty 1.1.0\nNo options were added, removed or changed\n") {
+		t.Errorf("Expected identical schemas, got %q", stdout)
+	}
+
+	// Versions lists the cached versions, and the embedded snapshot only if
+	// it was generated from a release
+	_, stdout, _ = run(t, "schema-diff", "-versions")
+	if !strings.HasPrefix(stdout, "1.0.0\n1.1.0\n") {
+		t.Errorf("Expected the cached versions, got %q", stdout)
+	}
+
+	if code, _, _ := run(t, "schema-diff", "0.1.0", "1.1.0"); code != ExitError {
+		t.Errorf("Expected exit code %d for an unknown version, got %d", ExitError, code)
+	}
+	if code, _, _ := run(t, "schema-diff", "-format", "yaml"); code != ExitUsage {
+		t.Errorf("Expected exit code %d for an unknown format, got %d", ExitUsage, code)
+	}
+}
@@ -0,0 +1,287 @@
+package cli
+
+import (
+	"cmp"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+
+	"github.com/otiai10/ghostconfig/internal/config"
+	"github.com/otiai10/ghostconfig/internal/i18n"
+	"github.com/otiai10/ghostconfig/internal/schema"
+)
+
+// ghostconfig validate [file...] - Check config files, for use in CI
+func runValidate(args []string) int {
+	fs := flag.NewFlagSet("validate", flag.ContinueOnError)
+	fs.Usage = usage(fs, "validate [flags] [file...]")
+	format := fs.String("format", "text", "Output format: text, json or sarif")
+	schemaFile := fs.String("schema", "", "Schema to check against instead of the installed Ghostty: a cached schema.json or the output of `ghostty +show-config --default --docs`")
+	strict := fs.Bool("strict", false, "Fail on warnings as well as errors")
+	if err := fs.Parse(args); err != nil {
+		return ExitUsage
+	}
+	if *format != "text" && *format != "json" && *format != "sarif" {
+		fs.Usage()
+		return ExitUsage
+	}
+	files := fs.Args()
+	if len(files) == 0 {
+		files = []string{config.DefaultPath()}
+	}
+
+	var options []schema.Option
+	var err error
+	if *schemaFile != "" {
+		options, err = schema.LoadFile(*schemaFile)
+	} else {
+		var sch schema.Schema
+		sch, err = schema.Load(false)
+		options = sch.Options
+		if err == nil && sch.Origin != schema.OriginGhostty {
+			fmt.Fprintf(os.Stderr, i18n.T("cli.schema_fallback")+"\n", sch.Describe())
+		}
+	}
+	if err == nil && len(options) == 0 {
+		err = fmt.Errorf("%s", i18n.T("error.no_options"))
+	}
+	if err != nil {
+		return fail(err)
+	}
+
+	// Keybinds are checked against Ghostty's defaults when it can list them
+	defaults, _ := schema.ListKeybinds()
+	diags, err := validateFiles(files, options, defaults)
+	if err != nil {
+		return fail(err)
+	}
+
+	switch *format {
+	case "json":
+		err = writeJSON(os.Stdout, diags)
+	case "sarif":
+		err = writeSARIF(os.Stdout, diags)
+	default:
+		writeText(os.Stdout, diags)
+	}
+	if err != nil {
+		return fail(err)
+	}
+
+	for _, d := range diags {
+		if d.Severity == config.SeverityError || (*strict && d.Severity == config.SeverityWarning) {
+			return ExitInvalid
+		}
+	}
+	return ExitOK
+}
+
+// validateFiles loads each file with its includes and returns the problems
+// found by Diagnose and, if it can be run, by Ghostty. Files included more
+// than once are reported once.
+func validateFiles(files []string, options []schema.Option, defaults []schema.Keybind) ([]config.Diagnostic, error) {
+	diags := []config.Diagnostic{}
+	seen := make(map[config.Diagnostic]bool)
+	checked := make(map[string]bool)
+
+	for _, file := range files {
+		if _, err := os.Stat(file); err != nil {
+			return nil, err
+		}
+		cfg, err := config.Load(file)
+		if err != nil {
+			return nil, err
+		}
+
+		found := cfg.Diagnose(options, defaults)
+		// Lines already known to be broken need no second opinion
+		flagged := make(map[config.Source]bool)
+		for _, d := range found {
+			if d.Severity == config.SeverityError {
+				flagged[config.Source{File: d.File, Line: d.Line}] = true
+			}
+		}
+		for _, f := range cfg.Files {
+			if checked[f] {
+				continue
+			}
+			checked[f] = true
+			data, err := os.ReadFile(f)
+			if err != nil {
+				continue
+			}
+			problems, err := config.ValidateFile(f, data)
+			if err != nil {
+				break // Ghostty cannot be run; the schema checks stand alone
+			}
+			for _, p := range problems {
+				if flagged[config.Source{File: f, Line: p.Line}] {
+					continue
+				}
+				found = append(found, config.Diagnostic{
+					File:     f,
+					Line:     p.Line,
+					Column:   1,
+					Severity: config.SeverityError,
+					Rule:     config.RuleGhostty,
+					Message:  schema.ValidationError{Key: p.Key, Message: p.Message}.Error(),
+				})
+			}
+		}
+
+		slices.SortStableFunc(found, func(a, b config.Diagnostic) int {
+			return cmp.Or(
+				cmp.Compare(slices.Index(cfg.Files, a.File), slices.Index(cfg.Files, b.File)),
+				cmp.Compare(a.Line, b.Line),
+				cmp.Compare(a.Column, b.Column),
+			)
+		})
+		for _, d := range found {
+			if !seen[d] {
+				seen[d] = true
+				diags = append(diags, d)
+			}
+		}
+	}
+	return diags, nil
+}
+
+func writeText(w io.Writer, diags []config.Diagnostic) {
+	errs, warnings := 0, 0
+	for _, d := range diags {
+		fmt.Fprintf(w, "%s [%s]\n", d, d.Rule)
+		switch d.Severity {
+		case config.SeverityError:
+			errs++
+		case config.SeverityWarning:
+			warnings++
+		}
+	}
+	fmt.Fprintf(w, i18n.T("cli.validate_summary")+"\n", errs, warnings)
+}
+
+func writeJSON(w io.Writer, v any) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(v)
+}
+
+// ruleDescriptions describes each rule in SARIF output
+var ruleDescriptions = map[string]string{
+	config.RuleMalformedLine:     "Line is not a key = value entry",
+	config.RuleMissingKey:        "Entry has no key",
+	config.RuleUnterminatedQuote: "Quoted value is not closed",
+	config.RuleBOM:               "File starts with a UTF-8 byte order mark",
+	config.RuleLineEndings:       "File uses CRLF or mixed line endings",
+	config.RuleInclude:           "config-file include could not be loaded",
+	config.RuleUnknownKey:        "Key is not a Ghostty option",
+	config.RuleDuplicateKey:      "Key that takes one value is set more than once",
+	config.RuleInvalidValue:      "Value does not fit the type of its option",
+	config.RuleDeprecated:        "Option is deprecated",
+	config.RuleKeybindDuplicate:  "Keys are bound again, which replaces the earlier binding",
+	config.RuleKeybindPrefix:     "Key sequence starts with or continues another binding, which stops working",
+	config.RuleKeybindShadowed:   "Keybind replaces one of Ghostty's default bindings",
+	config.RuleGhostty:           "Rejected by ghostty +validate-config",
+}
+
+// writeSARIF writes diagnostics as a SARIF 2.1.0 log, which code scanning
+// services show as annotations on the offending lines
+func writeSARIF(w io.Writer, diags []config.Diagnostic) error {
+	type message struct {
+		Text string `json:"text"`
+	}
+	type region struct {
+		StartLine   int `json:"startLine"`
+		StartColumn int `json:"startColumn,omitempty"`
+	}
+	type artifactLocation struct {
+		URI string `json:"uri"`
+	}
+	type physicalLocation struct {
+		ArtifactLocation artifactLocation `json:"artifactLocation"`
+		Region           *region          `json:"region,omitempty"`
+	}
+	type location struct {
+		PhysicalLocation physicalLocation `json:"physicalLocation"`
+	}
+	type result struct {
+		RuleID    string     `json:"ruleId"`
+		Level     string     `json:"level"`
+		Message   message    `json:"message"`
+		Locations []location `json:"locations"`
+	}
+	type rule struct {
+		ID               string  `json:"id"`
+		ShortDescription message `json:"shortDescription"`
+	}
+
+	var rules []rule
+	ruleSeen := make(map[string]bool)
+	results := []result{}
+	for _, d := range diags {
+		if !ruleSeen[d.Rule] {
+			ruleSeen[d.Rule] = true
+			rules = append(rules, rule{ID: d.Rule, ShortDescription: message{ruleDescriptions[d.Rule]}})
+		}
+		loc := physicalLocation{ArtifactLocation: artifactLocation{URI: sarifURI(d.File)}}
+		if d.Line > 0 {
+			loc.Region = &region{StartLine: d.Line, StartColumn: d.Column}
+		}
+		level := "note"
+		switch d.Severity {
+		case config.SeverityError:
+			level = "error"
+		case config.SeverityWarning:
+			level = "warning"
+		}
+		results = append(results, result{
+			RuleID:    d.Rule,
+			Level:     level,
+			Message:   message{d.Message},
+			Locations: []location{{PhysicalLocation: loc}},
+		})
+	}
+	if rules == nil {
+		rules = []rule{}
+	}
+
+	type driver struct {
+		Name           string `json:"name"`
+		InformationURI string `json:"informationUri"`
+		Rules          []rule `json:"rules"`
+	}
+	type tool struct {
+		Driver driver `json:"driver"`
+	}
+	type run struct {
+		Tool    tool     `json:"tool"`
+		Results []result `json:"results"`
+	}
+	return writeJSON(w, struct {
+		Schema  string `json:"$schema"`
+		Version string `json:"version"`
+		Runs    []run  `json:"runs"`
+	}{
+		Schema:  "https://json.schemastore.org/sarif-2.1.0.json",
+		Version: "2.1.0",
+		Runs: []run{{
+			Tool:    tool{Driver: driver{Name: "ghostconfig", InformationURI: "https://github.com/otiai10/ghostconfig", Rules: rules}},
+			Results: results,
+		}},
+	})
+}
+
+// sarifURI returns path relative to the working directory where possible,
+// with forward slashes, as code scanning expects repository paths
+func sarifURI(path string) string {
+	if wd, err := os.Getwd(); err == nil {
+		if rel, err := filepath.Rel(wd, path); err == nil && filepath.IsLocal(rel) {
+			path = rel
+		}
+	}
+	return filepath.ToSlash(path)
+}
@@ -0,0 +1,50 @@
+package cli
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+var schemaFile = filepath.Join("testdata", "show-config-docs.txt")
+
+func TestValidateExitCodes(t *testing.T) {
+	tests := []struct {
+		name string
+		args []string
+		code int
+	}{
+		{"valid", []string{"testdata/valid.config"}, ExitOK},
+		{"errors", []string{"testdata/invalid.config"}, ExitInvalid},
+		{"warnings", []string{"testdata/warnings.config"}, ExitOK},
+		{"strict warnings", []string{"-strict", "testdata/warnings.config"}, ExitInvalid},
+		{"strict valid", []string{"-strict", "testdata/valid.config"}, ExitOK},
+		{"several files", []string{"testdata/valid.config", "testdata/invalid.config"}, ExitInvalid},
+		{"unknown format", []string{"-format", "xml", "testdata/valid.config"}, ExitUsage},
+		{"unknown flag", []string{"-nope"}, ExitUsage},
+		{"missing file", []string{"testdata/missing.config"}, ExitError},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			isolate(t)
+			code, _, stderr := run(t, "validate", append([]string{"-schema", schemaFile}, tt.args...)...)
+			if code != tt.code {
+				t.Errorf("Expected exit code %d, got %d (%s)", tt.code, code, stderr)
+			}
+		})
+	}
+}
+
+func TestValidateOutput(t *testing.T) {
+	for _, format := range []string{"text", "json", "sarif"} {
+		t.Run(format, func(t *testing.T) {
+			isolate(t)
+			_, stdout, _ := run(t, "validate", "-schema", schemaFile, "-format", format, "testdata/invalid.config")
+			golden(t, "invalid."+format, stdout)
+		})
+	}
+
+	isolate(t)
+	// A clean run is still a valid SARIF log
+	_, stdout, _ := run(t, "validate", "-schema", schemaFile, "-format", "sarif", "testdata/valid.config")
+	golden(t, "valid.sarif", stdout)
+}
@@ -0,0 +1,249 @@
+package cli
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	"github.com/otiai10/ghostconfig/internal/config"
+	"github.com/otiai10/ghostconfig/internal/i18n"
+)
+
+// ghostconfig get <key> - Print the effective value of an option
+func runGet(args []string) int {
+	fs := flag.NewFlagSet("get", flag.ContinueOnError)
+	fs.Usage = usage(fs, "get [flags] <key>")
+	flags := addConfigFlags(fs)
+	all := fs.Bool("all", false, "Print every value of a repeatable key, one per line")
+	if err := fs.Parse(args); err != nil {
+		return ExitUsage
+	}
+	if fs.NArg() != 1 {
+		fs.Usage()
+		return ExitUsage
+	}
+	key := fs.Arg(0)
+
+	options, err := loadOptions()
+	if err != nil {
+		return fail(err)
+	}
+	opt, ok := findOption(options, key)
+	if !ok {
+		return unknownKey(key)
+	}
+	cfg, err := flags.load()
+	if err != nil {
+		return fail(err)
+	}
+
+	values := cfg.GetAll(key)
+	if len(values) == 0 {
+		fmt.Println(opt.DefaultValue)
+		return ExitNotSet
+	}
+	if !*all {
+		values = values[len(values)-1:]
+	}
+	for _, v := range values {
+		fmt.Println(v)
+	}
+	return ExitOK
+}
+
+// ghostconfig set <key> <value>... - Set an option and save
+func runSet(args []string) int {
+	fs := flag.NewFlagSet("set", flag.ContinueOnError)
+	fs.Usage = usage(fs, "set [flags] <key> <value>...")
+	flags := addConfigFlags(fs)
+	add := fs.Bool("add", false, "Append the values to a repeatable key instead of replacing them")
+	force := fs.Bool("force", false, "Save values even if Ghostty rejects them")
+	if err := fs.Parse(args); err != nil {
+		return ExitUsage
+	}
+	if fs.NArg() < 2 {
+		fs.Usage()
+		return ExitUsage
+	}
+	key, values := fs.Arg(0), fs.Args()[1:]
+	if *add && !config.IsRepeatable(key) {
+		fmt.Fprintf(os.Stderr, i18n.T("cli.add_not_repeatable")+"\n", key)
+		return ExitUsage
+	}
+	if len(values) > 1 && !config.IsRepeatable(key) {
+		fmt.Fprintf(os.Stderr, i18n.T("cli.not_repeatable")+"\n", key)
+		return ExitUsage
+	}
+	for _, v := range values {
+		if err := config.CheckEntry(key, v); err != nil {
+			fmt.Fprintf(os.Stderr, i18n.T("msg.error")+"\n", err)
+			return ExitUsage
+		}
+	}
+
+	options, err := loadOptions()
+	if err != nil {
+		return fail(err)
+	}
+	if _, ok := findOption(options, key); !ok {
+		return unknownKey(key)
+	}
+	cfg, err := flags.load()
+	if err != nil {
+		return fail(err)
+	}
+
+	depth := cfg.UndoDepth()
+	switch {
+	case *add:
+		for _, v := range values {
+			if err = cfg.Add(key, v); err != nil {
+				break
+			}
+		}
+	case len(values) > 1:
+		err = cfg.SetAll(key, values)
+	default:
+		err = cfg.Set(key, values[0])
+	}
+	if err != nil {
+		return fail(err)
+	}
+
+	if !*force {
+		// Ghostty that cannot be run leaves the values unchecked, and
+		// problems the file already had are not blamed on the new values
+		problems, err := cfg.ValidateEdit(key, depth)
+		if err == nil && len(problems) > 0 {
+			for _, p := range problems {
+				fmt.Fprintln(os.Stderr, p)
+			}
+			return ExitInvalid
+		}
+	}
+	return save(cfg)
+}
+
+// ghostconfig unset <key> - Remove an option so Ghostty uses its default
+func runUnset(args []string) int {
+	fs := flag.NewFlagSet("unset", flag.ContinueOnError)
+	fs.Usage = usage(fs, "unset [flags] <key>")
+	flags := addConfigFlags(fs)
+	if err := fs.Parse(args); err != nil {
+		return ExitUsage
+	}
+	if fs.NArg() != 1 {
+		fs.Usage()
+		return ExitUsage
+	}
+	key := fs.Arg(0)
+
+	options, err := loadOptions()
+	if err != nil {
+		return fail(err)
+	}
+	cfg, err := flags.load()
+	if err != nil {
+		return fail(err)
+	}
+	// Keys Ghostty does not know can still be removed from the file
+	if _, ok := findOption(options, key); !ok && len(cfg.GetAll(key)) == 0 {
+		return unknownKey(key)
+	}
+
+	cfg.Unset(key)
+	return save(cfg)
+}
+
+// listEntry is an option in the output of list -json
+type listEntry struct {
+	Key      string          `json:"key"`
+	Value    string          `json:"value"`
+	Values   []string        `json:"values"`
+	Default  string          `json:"default"`
+	Modified bool            `json:"modified"`
+	Sources  []config.Source `json:"sources,omitempty"`
+}
+
+// ghostconfig list - Print options with their values
+func runList(args []string) int {
+	fs := flag.NewFlagSet("list", flag.ContinueOnError)
+	fs.Usage = usage(fs, "list [flags]")
+	flags := addConfigFlags(fs)
+	modified := fs.Bool("modified", false, "Only list options set in the config")
+	asJSON := fs.Bool("json", false, "Print JSON")
+	if err := fs.Parse(args); err != nil {
+		return ExitUsage
+	}
+
+	options, err := loadOptions()
+	if err != nil {
+		return fail(err)
+	}
+	cfg, err := flags.load()
+	if err != nil {
+		return fail(err)
+	}
+
+	entries := []listEntry{}
+	for _, opt := range options {
+		values := cfg.GetAll(opt.Key)
+		if *modified && len(values) == 0 {
+			continue
+		}
+		e := listEntry{
+			Key:      opt.Key,
+			Value:    opt.DefaultValue,
+			Values:   values,
+			Default:  opt.DefaultValue,
+			Modified: len(values) > 0,
+			Sources:  cfg.Sources[opt.Key],
+		}
+		if e.Modified {
+			e.Value = cfg.Get(opt.Key)
+		} else {
+			e.Values = []string{}
+		}
+		entries = append(entries, e)
+	}
+
+	if *asJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(entries); err != nil {
+			return fail(err)
+		}
+		return ExitOK
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	for _, e := range entries {
+		if !e.Modified {
+			fmt.Fprintf(w, "%s\t%s\t%s\n", e.Key, e.Value, i18n.T("tui.default"))
+			continue
+		}
+		for i, v := range e.Values {
+			fmt.Fprintf(w, "%s\t%s\t%s\n", e.Key, v, e.Sources[i])
+		}
+	}
+	return flushed(w)
+}
+
+// save writes the config, reporting conflicts with changes made on disk
+func save(cfg *config.Config) int {
+	err := cfg.Save()
+	var conflictErr *config.ConflictError
+	if errors.As(err, &conflictErr) {
+		for _, c := range conflictErr.Conflicts {
+			fmt.Fprintf(os.Stderr, i18n.T("cli.conflict")+"\n", c.File, c.Key)
+		}
+		return ExitError
+	}
+	if err != nil {
+		return fail(err)
+	}
+	return ExitOK
+}
@@ -0,0 +1,107 @@
+package cli
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// tempConfig writes a config file to a temporary directory
+func tempConfig(t *testing.T, content string) string {
+	path := filepath.Join(t.TempDir(), "config")
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestGet(t *testing.T) {
+	isolate(t)
+	path := tempConfig(t, "keybind = ctrl+t=new_tab\nkeybind = ctrl+w=close_surface\nfont-size = 14\n")
+
+	if code, stdout, _ := run(t, "get", "-file", path, "font-size"); code != ExitOK || stdout != "14\n" {
+		t.Errorf("Expected 14, got %d %q", code, stdout)
+	}
+	if code, stdout, _ := run(t, "get", "-file", path, "keybind"); code != ExitOK || stdout != "ctrl+w=close_surface\n" {
+		t.Errorf("Expected the last keybind, got %d %q", code, stdout)
+	}
+	if code, stdout, _ := run(t, "get", "-file", path, "-all", "keybind"); code != ExitOK || stdout != "ctrl+t=new_tab\nctrl+w=close_surface\n" {
+		t.Errorf("Expected every keybind, got %d %q", code, stdout)
+	}
+	if code, _, _ := run(t, "get", "-file", path, "cursor-style"); code != ExitNotSet {
+		t.Errorf("Expected exit code %d for an unset key, got %d", ExitNotSet, code)
+	}
+	if code, _, _ := run(t, "get", "-file", path, "not-an-option"); code != ExitUnknownKey {
+		t.Errorf("Expected exit code %d for an unknown key, got %d", ExitUnknownKey, code)
+	}
+	if code, _, _ := run(t, "get", "-file", path); code != ExitUsage {
+		t.Errorf("Expected exit code %d without a key, got %d", ExitUsage, code)
+	}
+}
+
+func TestSet(t *testing.T) {
+	isolate(t)
+	path := tempConfig(t, "font-size = 14\nkeybind = ctrl+t=new_tab\n")
+	read := func() string {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return string(data)
+	}
+
+	if code, _, stderr := run(t, "set", "-file", path, "-backups", "0", "font-size", "15"); code != ExitOK {
+		t.Fatalf("Expected exit code %d, got %d (%s)", ExitOK, code, stderr)
+	}
+	if !strings.Contains(read(), "font-size = 15\n") {
+		t.Errorf("Expected font-size to be replaced, got\n%s", read())
+	}
+
+	if code, _, _ := run(t, "set", "-file", path, "-backups", "0", "-add", "keybind", "ctrl+w=close_surface"); code != ExitOK {
+		t.Fatalf("Expected exit code %d, got %d", ExitOK, code)
+	}
+	if !strings.Contains(read(), "keybind = ctrl+t=new_tab\n") || !strings.Contains(read(), "keybind = ctrl+w=close_surface\n") {
+		t.Errorf("Expected both keybinds, got\n%s", read())
+	}
+
+	// Keys that take one value cannot be added to or given several values,
+	// and values with line breaks and empty keys are rejected
+	before := read()
+	for _, args := range [][]string{
+		{"-add", "font-size", "16"},
+		{"font-size", "16", "17"},
+		{"font-size", "16\nkeybind = ctrl+q=quit"},
+		{"-add", "keybind", "ctrl+q=quit\r"},
+		{"", "16"},
+	} {
+		code, _, stderr := run(t, "set", append([]string{"-file", path, "-backups", "0"}, args...)...)
+		if code != ExitUsage || stderr == "" {
+			t.Errorf("Expected exit code %d with a message for %v, got %d %q", ExitUsage, args, code, stderr)
+		}
+	}
+	if read() != before {
+		t.Errorf("Expected the file to be unchanged, got\n%s", read())
+	}
+
+	if code, _, _ := run(t, "set", "-file", path, "not-an-option", "1"); code != ExitUnknownKey {
+		t.Errorf("Expected exit code %d for an unknown key, got %d", ExitUnknownKey, code)
+	}
+}
+
+func TestUnset(t *testing.T) {
+	isolate(t)
+	path := tempConfig(t, "font-size = 14\nold-option = 1\n")
+
+	for _, key := range []string{"font-size", "old-option"} {
+		if code, _, _ := run(t, "unset", "-file", path, "-backups", "0", key); code != ExitOK {
+			t.Errorf("Expected exit code %d for %s, got %d", ExitOK, key, code)
+		}
+	}
+	if data, _ := os.ReadFile(path); strings.TrimSpace(string(data)) != "" {
+		t.Errorf("Expected an empty config, got\n%s", data)
+	}
+	if code, _, _ := run(t, "unset", "-file", path, "old-option"); code != ExitUnknownKey {
+		t.Errorf("Expected exit code %d for an unknown key that is not set, got %d", ExitUnknownKey, code)
+	}
+}
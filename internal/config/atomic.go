@@ -0,0 +1,106 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+)
+
+// writeFileAtomic replaces the contents of path without ever leaving a
+// partially written file behind. The data goes to a temporary file in the
+// same directory, which is synced and then renamed over the original.
+// The original mode and ownership are kept, and symlinks are followed so
+// that dotfile managers keep pointing at the real file.
+func writeFileAtomic(path string, data []byte) error {
+	target, err := resolveSymlink(path)
+	if err != nil {
+		return err
+	}
+
+	mode := os.FileMode(0644)
+	info, err := os.Stat(target)
+	if err == nil {
+		mode = info.Mode().Perm()
+	} else if !os.IsNotExist(err) {
+		return err
+	}
+
+	dir := filepath.Dir(target)
+	tmp, err := os.CreateTemp(dir, "."+filepath.Base(target)+".tmp-*")
+	if err != nil {
+		return err
+	}
+	tmpName := tmp.Name()
+	defer func() {
+		// Only left over if something failed before the rename
+		os.Remove(tmpName)
+	}()
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	if err := os.Chmod(tmpName, mode); err != nil {
+		return err
+	}
+	if info != nil {
+		if err := chown(tmpName, info); err != nil {
+			return err
+		}
+	}
+
+	if err := os.Rename(tmpName, target); err != nil {
+		return err
+	}
+	syncDir(dir)
+	return nil
+}
+
+// resolveSymlink follows path through any chain of symlinks.
+// Unlike filepath.EvalSymlinks it also resolves links whose target
+// does not exist yet, so the file is created where the link points.
+func resolveSymlink(path string) (string, error) {
+	for range 40 {
+		info, err := os.Lstat(path)
+		if os.IsNotExist(err) {
+			return path, nil
+		}
+		if err != nil {
+			return "", err
+		}
+		if info.Mode()&os.ModeSymlink == 0 {
+			return path, nil
+		}
+		link, err := os.Readlink(path)
+		if err != nil {
+			return "", err
+		}
+		if !filepath.IsAbs(link) {
+			link = filepath.Join(filepath.Dir(path), link)
+		}
+		path = link
+	}
+	return "", &os.PathError{Op: "readlink", Path: path, Err: os.ErrInvalid}
+}
+
+// syncDir flushes the directory entry of a rename to disk.
+// Not every platform supports syncing a directory, so errors are ignored.
+func syncDir(dir string) {
+	d, err := os.Open(dir)
+	if err != nil {
+		return
+	}
+	d.Sync()
+	d.Close()
+}
+
+// WriteFile replaces the contents of path atomically, like Save does
+func WriteFile(path string, data []byte) error {
+	return writeFileAtomic(path, data)
+}
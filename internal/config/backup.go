@@ -0,0 +1,251 @@
+package config
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// DefaultBackupKeep is the number of snapshots kept per config file
+const DefaultBackupKeep = 20
+
+// snapshotTimeFormat is used for snapshot IDs; it sorts chronologically
+const snapshotTimeFormat = "20060102-150405.000"
+
+// BackupStore keeps rotating, timestamped snapshots of config files.
+// Each config file gets its own directory inside Dir, holding the
+// original path and one file per snapshot.
+type BackupStore struct {
+	Dir  string
+	Keep int
+}
+
+// Snapshot describes a saved copy of a config file
+type Snapshot struct {
+	ID   string    `json:"id"`
+	File string    `json:"file"`
+	Time time.Time `json:"time"`
+	Size int64     `json:"size"`
+
+	path string
+}
+
+// NewBackupStore creates a store in dir keeping up to keep snapshots per file.
+// An empty dir uses DefaultBackupDir.
+func NewBackupStore(dir string, keep int) *BackupStore {
+	if dir == "" {
+		dir = DefaultBackupDir()
+	}
+	return &BackupStore{Dir: dir, Keep: keep}
+}
+
+// DefaultBackupDir returns $XDG_STATE_HOME/ghostconfig/backups,
+// falling back to ~/.local/state/ghostconfig/backups
+func DefaultBackupDir() string {
+	if state := os.Getenv("XDG_STATE_HOME"); state != "" {
+		return filepath.Join(state, "ghostconfig", "backups")
+	}
+	home, _ := os.UserHomeDir()
+	return filepath.Join(home, ".local", "state", "ghostconfig", "backups")
+}
+
+// fileDir returns the snapshot directory of a config file
+func (s *BackupStore) fileDir(file string) string {
+	abs, err := filepath.Abs(file)
+	if err != nil {
+		abs = file
+	}
+	sum := sha256.Sum256([]byte(abs))
+	return filepath.Join(s.Dir, filepath.Base(abs)+"-"+hex.EncodeToString(sum[:4]))
+}
+
+// Snapshot saves the current contents of file.
+// Nothing is saved if the file does not exist or matches the latest snapshot.
+func (s *BackupStore) Snapshot(file string) error {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return err
+	}
+
+	snapshots, err := s.List(file)
+	if err != nil {
+		return err
+	}
+	if len(snapshots) > 0 {
+		if latest, err := os.ReadFile(snapshots[0].path); err == nil && bytes.Equal(latest, data) {
+			return nil
+		}
+	}
+
+	dir := s.fileDir(file)
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return err
+	}
+	abs, _ := filepath.Abs(file)
+	if err := os.WriteFile(filepath.Join(dir, "path"), []byte(abs), 0600); err != nil {
+		return err
+	}
+	// Snapshots taken within the same millisecond get the next free name
+	t := time.Now().UTC()
+	for {
+		name := filepath.Join(dir, t.Format(snapshotTimeFormat))
+		f, err := os.OpenFile(name, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0600)
+		if os.IsExist(err) {
+			t = t.Add(time.Millisecond)
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		_, err = f.Write(data)
+		if cerr := f.Close(); err == nil {
+			err = cerr
+		}
+		if err != nil {
+			return err
+		}
+		break
+	}
+
+	return s.prune(file)
+}
+
+// prune removes the oldest snapshots of file beyond Keep
+func (s *BackupStore) prune(file string) error {
+	if s.Keep <= 0 {
+		return nil
+	}
+	snapshots, err := s.List(file)
+	if err != nil {
+		return err
+	}
+	for _, snap := range snapshots[min(s.Keep, len(snapshots)):] {
+		if err := os.Remove(snap.path); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// List returns the snapshots of the given files, newest first.
+// Without files, the snapshots of every file in the store are returned.
+func (s *BackupStore) List(files ...string) ([]Snapshot, error) {
+	var dirs []string
+	if len(files) == 0 {
+		entries, err := os.ReadDir(s.Dir)
+		if err != nil && !os.IsNotExist(err) {
+			return nil, err
+		}
+		for _, e := range entries {
+			if e.IsDir() {
+				dirs = append(dirs, filepath.Join(s.Dir, e.Name()))
+			}
+		}
+	}
+	for _, file := range files {
+		dirs = append(dirs, s.fileDir(file))
+	}
+
+	var snapshots []Snapshot
+	for _, dir := range dirs {
+		entries, err := os.ReadDir(dir)
+		if err != nil {
+			if os.IsNotExist(err) {
+				continue
+			}
+			return nil, err
+		}
+		original, _ := os.ReadFile(filepath.Join(dir, "path"))
+		// The hash suffix of the directory tells files apart in IDs
+		base := filepath.Base(dir)
+		hash := base[strings.LastIndex(base, "-")+1:]
+		for _, e := range entries {
+			t, err := time.Parse(snapshotTimeFormat, e.Name())
+			if err != nil {
+				continue // "path" and stray files
+			}
+			info, err := e.Info()
+			if err != nil {
+				return nil, err
+			}
+			snapshots = append(snapshots, Snapshot{
+				ID:   e.Name() + "-" + hash,
+				File: string(original),
+				Time: t.Local(),
+				Size: info.Size(),
+				path: filepath.Join(dir, e.Name()),
+			})
+		}
+	}
+
+	sort.SliceStable(snapshots, func(i, j int) bool {
+		return snapshots[i].Time.After(snapshots[j].Time)
+	})
+	return snapshots, nil
+}
+
+// Find returns the snapshot whose ID starts with id.
+// The prefix must match exactly one snapshot.
+func (s *BackupStore) Find(id string) (*Snapshot, error) {
+	snapshots, err := s.List()
+	if err != nil {
+		return nil, err
+	}
+	var found []Snapshot
+	for _, snap := range snapshots {
+		if snap.ID == id {
+			return &snap, nil
+		}
+		if strings.HasPrefix(snap.ID, id) {
+			found = append(found, snap)
+		}
+	}
+	switch len(found) {
+	case 0:
+		return nil, fmt.Errorf("snapshot not found: %s", id)
+	case 1:
+		return &found[0], nil
+	default:
+		return nil, fmt.Errorf("snapshot id %s is ambiguous (%d matches)", id, len(found))
+	}
+}
+
+// Read returns the contents of a snapshot
+func (s *BackupStore) Read(snap *Snapshot) ([]byte, error) {
+	return os.ReadFile(snap.path)
+}
+
+// Diff returns a unified diff from a snapshot to the current contents of its file
+func (s *BackupStore) Diff(snap *Snapshot) (string, error) {
+	old, err := s.Read(snap)
+	if err != nil {
+		return "", err
+	}
+	current, err := os.ReadFile(snap.File)
+	if err != nil && !os.IsNotExist(err) {
+		return "", err
+	}
+	return UnifiedDiff(snap.ID, snap.File, old, current), nil
+}
+
+// Restore writes a snapshot back to its original file.
+// The current contents are snapshotted first, so a restore can be undone.
+func (s *BackupStore) Restore(snap *Snapshot) error {
+	data, err := s.Read(snap)
+	if err != nil {
+		return err
+	}
+	if err := s.Snapshot(snap.File); err != nil {
+		return err
+	}
+	return writeFileAtomic(snap.File, data)
+}
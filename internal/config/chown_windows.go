@@ -0,0 +1,8 @@
+package config
+
+import "os"
+
+// chown is a no-op on Windows, where files have no Unix owner
+func chown(path string, info os.FileInfo) error {
+	return nil
+}
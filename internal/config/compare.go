@@ -0,0 +1,100 @@
+package config
+
+import (
+	"slices"
+	"sort"
+
+	"github.com/otiai10/ghostconfig/internal/schema"
+)
+
+// Change is an option whose effective values differ between two configs.
+// An option that is not set takes its default value.
+type Change struct {
+	Key     string   `json:"key"`
+	Section string   `json:"section"`
+	From    []string `json:"from"`
+	To      []string `json:"to"`
+}
+
+// Defaults returns the default values of options as a values map.
+// Options without a default have no values.
+func Defaults(options []schema.Option) map[string][]string {
+	defaults := make(map[string][]string, len(options))
+	for _, opt := range options {
+		if opt.DefaultValue == "" {
+			defaults[opt.Key] = []string{}
+		} else {
+			defaults[opt.Key] = []string{opt.DefaultValue}
+		}
+	}
+	return defaults
+}
+
+// Compare returns the options whose effective values differ between from
+// and to, ordered by category (see schema.ExtractSection) and then key.
+// Repeatable keys are compared value by value, in order; for other keys
+// only the last value counts, as in Ghostty. Keys missing from a side
+// take their default value from options. Values are compared as Ghostty
+// reads them (see schema.SameValue), so "#FFF" equals "ffffff".
+func Compare(from, to map[string][]string, options []schema.Option) []Change {
+	defaults := Defaults(options)
+	byKey := make(map[string]schema.Option, len(options))
+	for _, opt := range options {
+		byKey[opt.Key] = opt
+	}
+	keys := make(map[string]bool)
+	for _, m := range []map[string][]string{from, to} {
+		for key := range m {
+			keys[key] = true
+		}
+	}
+
+	var changes []Change
+	for key := range keys {
+		f := effective(key, from[key], defaults[key])
+		t := effective(key, to[key], defaults[key])
+		opt, ok := byKey[key]
+		if !ok {
+			opt = schema.Option{Key: key}
+		}
+		if slices.EqualFunc(f, t, func(a, b string) bool { return schema.SameValue(opt, a, b) }) {
+			continue
+		}
+		changes = append(changes, Change{Key: key, Section: schema.ExtractSection(key), From: f, To: t})
+	}
+
+	sort.Slice(changes, func(i, j int) bool {
+		ci, cj := schema.CategoryIndex(changes[i].Section), schema.CategoryIndex(changes[j].Section)
+		if ci != cj {
+			return ci < cj
+		}
+		return changes[i].Key < changes[j].Key
+	})
+	return changes
+}
+
+// effective returns the values of key that Ghostty uses
+func effective(key string, values, defaults []string) []string {
+	if len(values) == 0 {
+		values = defaults
+	}
+	if !IsRepeatable(key) && len(values) > 1 {
+		values = values[len(values)-1:]
+	}
+	if values == nil {
+		values = []string{}
+	}
+	return values
+}
+
+// Affected returns the keys set in values that changes remove or give a
+// new default, in the order of changes
+func Affected(values map[string][]string, changes []schema.OptionChange) []string {
+	affected := []string{}
+	for _, c := range changes {
+		if c.Kind != schema.ChangeAdded && len(values[c.Key]) > 0 {
+			affected = append(affected, c.Key)
+		}
+	}
+	return affected
+}
@@ -1,18 +1,63 @@
 package config
 
 import (
-	"bufio"
+	"bytes"
 	"fmt"
 	"os"
 	"path/filepath"
 	"runtime"
-	"strings"
+
+	"github.com/otiai10/ghostconfig/internal/schema"
 )
 
-// Config represents the current Ghostty configuration
+// Config represents the current Ghostty configuration.
+// Values holds every value of a key in load order, so repeatable keys
+// such as keybind or palette keep all of their entries. Sources runs
+// parallel to Values and records where each value is defined.
 type Config struct {
-	Path   string
-	Values map[string]string
+	Path    string
+	Values  map[string][]string
+	Sources map[string][]Source
+
+	// Files lists the main config file followed by every file it includes
+	Files []string
+	// IncludeErrors holds config-file entries that could not be loaded
+	IncludeErrors []error
+	// Diagnostics holds problems found in the lines of the files;
+	// see Diagnose for the full list including unknown keys
+	Diagnostics []Diagnostic
+
+	// Backups receives a snapshot of each file before Save changes it.
+	// Nil disables backups.
+	Backups *BackupStore
+
+	// Edit journal for Undo and Redo
+	undo []edit
+	redo []edit
+
+	// Contents of each file as last read or written, the base of merges
+	// with changes made by other programs, and the conflicts they caused
+	base      map[string]fileState
+	conflicts []Conflict
+}
+
+// Source is the location of a value in a config file.
+// Line is 1-based and 0 for values that have not been saved yet.
+type Source struct {
+	File string `json:"file"`
+	Line int    `json:"line"`
+}
+
+func (s Source) String() string {
+	if s.Line == 0 {
+		return s.File
+	}
+	return fmt.Sprintf("%s:%d", s.File, s.Line)
+}
+
+// IsRepeatable reports whether a key may appear multiple times in a config
+func IsRepeatable(key string) bool {
+	return schema.IsRepeatable(key)
 }
 
 // DefaultPath returns the default config file path
@@ -50,112 +95,276 @@ func getPrimaryConfigPath() string {
 	return ""
 }
 
-// Load reads the config file and returns the configuration
+// Load reads the config file and every file it includes via config-file
 func Load(path string) (*Config, error) {
 	if path == "" {
 		path = DefaultPath()
 	}
 
 	cfg := &Config{
-		Path:   path,
-		Values: make(map[string]string),
+		Path:    path,
+		Values:  make(map[string][]string),
+		Sources: make(map[string][]Source),
+		base:    make(map[string]fileState),
 	}
 
-	file, err := os.Open(path)
-	if err != nil {
+	if err := cfg.loadFile(path, make(map[string]bool)); err != nil {
 		if os.IsNotExist(err) {
+			cfg.Files = []string{path}
 			return cfg, nil // Return empty config if file doesn't exist
 		}
 		return nil, err
 	}
-	defer file.Close()
 
-	scanner := bufio.NewScanner(file)
-	for scanner.Scan() {
-		line := strings.TrimSpace(scanner.Text())
-		if line == "" || strings.HasPrefix(line, "#") {
+	return cfg, nil
+}
+
+// Save writes the configuration back to the files that define each value.
+// Only lines whose values changed are rewritten; everything else in the
+// files, including comments and formatting, is kept as it was.
+//
+// Files changed by another program since they were read are merged first.
+// If that leads to conflicts, those files are left alone and a
+// *ConflictError is returned; see Resolve.
+func (c *Config) Save() error {
+	files := c.Files
+	if len(files) == 0 {
+		files = []string{c.Path}
+	}
+
+	for _, file := range files {
+		data, err := os.ReadFile(file)
+		if err != nil && !os.IsNotExist(err) {
+			return err
+		}
+		if c.changedOnDisk(file, data) {
+			info, _ := os.Stat(file)
+			c.merge(file, data, info)
+		}
+		if c.hasConflicts(file) {
+			continue
+		}
+
+		out, renderErr := c.render(file, data)
+		if renderErr != nil {
+			return renderErr
+		}
+		if err == nil && bytes.Equal(out, data) {
 			continue
 		}
-		parts := strings.SplitN(line, "=", 2)
-		if len(parts) == 2 {
-			key := strings.TrimSpace(parts[0])
-			value := strings.TrimSpace(parts[1])
-			cfg.Values[key] = value
+		if c.Backups != nil {
+			if err := c.Backups.Snapshot(file); err != nil {
+				return fmt.Errorf("backup %s: %w", file, err)
+			}
+		}
+		if err := writeFileAtomic(file, out); err != nil {
+			return err
 		}
+		info, _ := os.Stat(file)
+		c.setBase(file, out, info)
+	}
+
+	if len(c.conflicts) > 0 {
+		return &ConflictError{Conflicts: c.conflicts}
 	}
 
-	return cfg, scanner.Err()
+	// Refresh line numbers, which shift as lines are added or removed
+	return c.Reload()
 }
 
-// Save writes the configuration to the file
-func (c *Config) Save() error {
-	// Read existing file to preserve comments
-	existingLines, err := c.readExistingLines()
-	if err != nil && !os.IsNotExist(err) {
+// Reload reads the config files again, replacing all values
+func (c *Config) Reload() error {
+	fresh, err := Load(c.Path)
+	if err != nil {
 		return err
 	}
+	c.Values, c.Sources, c.Files = fresh.Values, fresh.Sources, fresh.Files
+	c.IncludeErrors, c.Diagnostics = fresh.IncludeErrors, fresh.Diagnostics
+	c.base, c.conflicts = fresh.base, nil
+	return nil
+}
 
-	file, err := os.Create(c.Path)
-	if err != nil {
-		return err
+// valuesIn returns the values that belong to file.
+// Every key is included, so values removed from the file are dropped from it.
+func (c *Config) valuesIn(file string) map[string][]string {
+	values := make(map[string][]string, len(c.Values))
+	for key, all := range c.Values {
+		values[key] = []string{}
+		for i, src := range c.sourcesOf(key) {
+			if src.File == file {
+				values[key] = append(values[key], all[i])
+			}
+		}
 	}
-	defer file.Close()
+	return values
+}
 
-	written := make(map[string]bool)
+// sourcesOf returns the sources of key, one per value
+func (c *Config) sourcesOf(key string) []Source {
+	srcs := c.Sources[key]
+	for len(srcs) < len(c.Values[key]) {
+		srcs = append(srcs, Source{File: c.Path})
+	}
+	return srcs[:len(c.Values[key])]
+}
 
-	// Write existing lines, updating values
-	for _, line := range existingLines {
-		trimmed := strings.TrimSpace(line)
-		if trimmed == "" || strings.HasPrefix(trimmed, "#") {
-			fmt.Fprintln(file, line)
-			continue
-		}
+func (c *Config) setSources(key string, srcs []Source) {
+	if c.Sources == nil {
+		c.Sources = make(map[string][]Source)
+	}
+	c.Sources[key] = srcs
+}
 
-		parts := strings.SplitN(trimmed, "=", 2)
-		if len(parts) == 2 {
-			key := strings.TrimSpace(parts[0])
-			if value, ok := c.Values[key]; ok {
-				fmt.Fprintf(file, "%s = %s\n", key, value)
-				written[key] = true
-			} else {
-				fmt.Fprintln(file, line)
-			}
-		} else {
-			fmt.Fprintln(file, line)
+// fileFor returns the file new values of key are written to:
+// the file holding its effective value, or the main config file.
+func (c *Config) fileFor(key string) string {
+	if srcs := c.sourcesOf(key); len(srcs) > 0 {
+		return srcs[len(srcs)-1].File
+	}
+	return c.Path
+}
+
+// Source returns where the effective value of key is defined
+func (c *Config) Source(key string) (Source, bool) {
+	srcs := c.sourcesOf(key)
+	if len(srcs) == 0 {
+		return Source{}, false
+	}
+	return srcs[len(srcs)-1], true
+}
+
+// Set replaces the effective value of a key.
+// Values of the key in other files are kept; only the file that holds
+// the effective value is changed. Values with line breaks and empty keys
+// are rejected (see CheckEntry).
+func (c *Config) Set(key, value string) error {
+	if err := CheckEntry(key, value); err != nil {
+		return err
+	}
+	defer c.record(key, c.state(key))
+	file := c.fileFor(key)
+	var values []string
+	var srcs []Source
+	for i, src := range c.sourcesOf(key) {
+		if src.File != file {
+			values = append(values, c.Values[key][i])
+			srcs = append(srcs, src)
 		}
 	}
+	c.Values[key] = append(values, value)
+	c.setSources(key, append(srcs, Source{File: file}))
+	return nil
+}
+
+// Unset removes every value of a key, in every file, so that Ghostty
+// falls back to its default. Comments around the removed lines are kept,
+// except those attached to a single entry (see Document.LeadingComments).
+func (c *Config) Unset(key string) {
+	defer c.record(key, c.state(key))
+	c.Values[key] = []string{}
+	c.setSources(key, nil)
+}
+
+// Get returns the effective value of a key.
+// For keys that appear more than once, the last value wins as in Ghostty.
+func (c *Config) Get(key string) string {
+	values := c.Values[key]
+	if len(values) == 0 {
+		return ""
+	}
+	return values[len(values)-1]
+}
 
-	// Append new values
-	for key, value := range c.Values {
-		if !written[key] {
-			fmt.Fprintf(file, "%s = %s\n", key, value)
+// GetAll returns all values of a key in file order
+func (c *Config) GetAll(key string) []string {
+	return c.Values[key]
+}
+
+// SetAll replaces all values of a key, keeping the given order.
+// Values keep the file of the value previously at the same position.
+func (c *Config) SetAll(key string, values []string) error {
+	for _, value := range values {
+		if err := CheckEntry(key, value); err != nil {
+			return err
 		}
 	}
+	defer c.record(key, c.state(key))
+	srcs := c.sourcesOf(key)
+	file := c.fileFor(key)
+	if len(srcs) > len(values) {
+		srcs = srcs[:len(values)]
+	}
+	for len(srcs) < len(values) {
+		srcs = append(srcs, Source{File: file})
+	}
+	c.Values[key] = append([]string(nil), values...)
+	c.setSources(key, srcs)
+	return nil
+}
 
+// SetAt replaces the value at index
+func (c *Config) SetAt(key string, index int, value string) error {
+	if err := CheckEntry(key, value); err != nil {
+		return err
+	}
+	defer c.record(key, c.state(key))
+	if err := c.checkIndex(key, index); err != nil {
+		return err
+	}
+	c.Values[key][index] = value
 	return nil
 }
 
-func (c *Config) readExistingLines() ([]string, error) {
-	file, err := os.Open(c.Path)
-	if err != nil {
-		return nil, err
+// Add appends a value to a key
+func (c *Config) Add(key, value string) error {
+	if err := CheckEntry(key, value); err != nil {
+		return err
 	}
-	defer file.Close()
+	defer c.record(key, c.state(key))
+	srcs := append(c.sourcesOf(key), Source{File: c.fileFor(key)})
+	c.Values[key] = append(c.Values[key], value)
+	c.setSources(key, srcs)
+	return nil
+}
 
-	var lines []string
-	scanner := bufio.NewScanner(file)
-	for scanner.Scan() {
-		lines = append(lines, scanner.Text())
+// RemoveAt removes the value at index.
+// The key stays in the config, so Save drops its lines from the file.
+func (c *Config) RemoveAt(key string, index int) error {
+	defer c.record(key, c.state(key))
+	if err := c.checkIndex(key, index); err != nil {
+		return err
 	}
-	return lines, scanner.Err()
+	values, srcs := c.Values[key], c.sourcesOf(key)
+	c.Values[key] = append(values[:index:index], values[index+1:]...)
+	c.setSources(key, append(srcs[:index:index], srcs[index+1:]...))
+	return nil
 }
 
-// Set updates a configuration value
-func (c *Config) Set(key, value string) {
-	c.Values[key] = value
+// Move moves the value at index from to index to, shifting the others.
+// Positions keep their files, so a value moved past a file boundary
+// is written to the file of its new position.
+func (c *Config) Move(key string, from, to int) error {
+	defer c.record(key, c.state(key))
+	if err := c.checkIndex(key, from); err != nil {
+		return err
+	}
+	if err := c.checkIndex(key, to); err != nil {
+		return err
+	}
+	values := c.Values[key]
+	value := values[from]
+	if from < to {
+		copy(values[from:to], values[from+1:to+1])
+	} else {
+		copy(values[to+1:from+1], values[to:from])
+	}
+	values[to] = value
+	return nil
 }
 
-// Get returns a configuration value
-func (c *Config) Get(key string) string {
-	return c.Values[key]
+func (c *Config) checkIndex(key string, index int) error {
+	if index < 0 || index >= len(c.Values[key]) {
+		return fmt.Errorf("index %d out of range for %s (%d values)", index, key, len(c.Values[key]))
+	}
+	return nil
 }
@@ -1,10 +1,15 @@
 package config
 
 import (
+	"errors"
+	"fmt"
 	"os"
 	"path/filepath"
 	"runtime"
+	"strings"
 	"testing"
+
+	"github.com/otiai10/ghostconfig/internal/schema"
 )
 
 func TestDefaultPath(t *testing.T) {
@@ -46,3 +51,621 @@ func TestGetPrimaryConfigPath(t *testing.T) {
 		}
 	}
 }
+
+func TestRepeatableKeys(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config")
+	content := "# keys\nkeybind = ctrl+a=new_tab\nfont-size = 12\nkeybind = ctrl+b=close_surface\npalette = 0=#000000\n"
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg, err := Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := cfg.GetAll("keybind"); len(got) != 2 {
+		t.Fatalf("Expected 2 keybinds, got %v", got)
+	}
+	if got := cfg.Get("keybind"); got != "ctrl+b=close_surface" {
+		t.Errorf("Expected last keybind, got %s", got)
+	}
+
+	cfg.Add("keybind", "ctrl+c=copy_to_clipboard")
+	if err := cfg.Move("keybind", 2, 0); err != nil {
+		t.Fatal(err)
+	}
+	if err := cfg.RemoveAt("keybind", 1); err != nil {
+		t.Fatal(err)
+	}
+	if err := cfg.RemoveAt("keybind", 5); err == nil {
+		t.Error("Expected error for out of range index")
+	}
+	cfg.Add("palette", "1=#ff0000")
+	if err := cfg.Save(); err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := "# keys\nkeybind = ctrl+c=copy_to_clipboard\nfont-size = 12\nkeybind = ctrl+b=close_surface\npalette = 0=#000000\npalette = 1=#ff0000\n"
+	if string(data) != expected {
+		t.Errorf("Expected:\n%s\nGot:\n%s", expected, data)
+	}
+}
+
+func TestIncludes(t *testing.T) {
+	dir := t.TempDir()
+	main := filepath.Join(dir, "config")
+	shared := filepath.Join(dir, "shared")
+	files := map[string]string{
+		main:   "font-size = 12\nconfig-file = shared\nconfig-file = ?missing\n",
+		shared: "# shared\ntheme = Dracula\nfont-size = 13\nconfig-file = config\n",
+	}
+	for path, content := range files {
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	cfg, err := Load(main)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := cfg.Get("font-size"); got != "13" {
+		t.Errorf("Expected included value to win, got %s", got)
+	}
+	if src, _ := cfg.Source("theme"); src.File != shared || src.Line != 2 {
+		t.Errorf("Unexpected source for theme: %v", src)
+	}
+	if len(cfg.IncludeErrors) != 1 {
+		t.Errorf("Expected only the cycle to be reported, got %v", cfg.IncludeErrors)
+	}
+
+	cfg.Set("theme", "Nord")
+	cfg.Set("font-size", "14")
+	cfg.Set("background", "000000")
+	if err := cfg.Save(); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := map[string]string{
+		main:   "font-size = 12\nconfig-file = shared\nconfig-file = ?missing\n\n# --- Appearance ---\nbackground = 000000\n",
+		shared: "# shared\ntheme = Nord\nfont-size = 14\nconfig-file = config\n",
+	}
+	for path, want := range expected {
+		data, _ := os.ReadFile(path)
+		if string(data) != want {
+			t.Errorf("%s: expected:\n%s\ngot:\n%s", path, want, data)
+		}
+	}
+}
+
+func TestSaveFollowsSymlink(t *testing.T) {
+	dir := t.TempDir()
+	real := filepath.Join(dir, "dotfiles", "ghostty")
+	link := filepath.Join(dir, "config")
+	if err := os.MkdirAll(filepath.Dir(real), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(real, []byte("font-size = 12\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Symlink(real, link); err != nil {
+		t.Skip("symlinks not supported:", err)
+	}
+
+	cfg, err := Load(link)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cfg.Set("font-size", "14")
+	if err := cfg.Save(); err != nil {
+		t.Fatal(err)
+	}
+
+	if info, err := os.Lstat(link); err != nil || info.Mode()&os.ModeSymlink == 0 {
+		t.Errorf("Expected %s to remain a symlink", link)
+	}
+	info, err := os.Stat(real)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if runtime.GOOS != "windows" && info.Mode().Perm() != 0600 {
+		t.Errorf("Expected mode 0600, got %v", info.Mode().Perm())
+	}
+	if data, _ := os.ReadFile(real); string(data) != "font-size = 14\n" {
+		t.Errorf("Unexpected content: %q", data)
+	}
+	if entries, _ := os.ReadDir(filepath.Dir(real)); len(entries) != 1 {
+		t.Errorf("Expected no temporary files, got %d entries", len(entries))
+	}
+}
+
+func TestBackups(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "config")
+	if err := os.WriteFile(path, []byte("font-size = 12\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg, err := Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cfg.Backups = NewBackupStore(filepath.Join(dir, "backups"), 2)
+	for _, size := range []string{"13", "14", "15"} {
+		cfg.Set("font-size", size)
+		if err := cfg.Save(); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	snapshots, err := cfg.Backups.List(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(snapshots) != 2 {
+		t.Fatalf("Expected 2 snapshots after rotation, got %d", len(snapshots))
+	}
+	data, _ := cfg.Backups.Read(&snapshots[0])
+	if string(data) != "font-size = 14\n" {
+		t.Errorf("Expected newest snapshot first, got %q", data)
+	}
+
+	snap, err := cfg.Backups.Find(snapshots[1].ID[:4])
+	if err == nil {
+		t.Errorf("Expected ambiguous prefix to fail, got %s", snap.ID)
+	}
+	snap, err = cfg.Backups.Find(snapshots[1].ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := cfg.Backups.Restore(snap); err != nil {
+		t.Fatal(err)
+	}
+	if data, _ := os.ReadFile(path); string(data) != "font-size = 13\n" {
+		t.Errorf("Expected restored content, got %q", data)
+	}
+}
+
+func TestUndoRedo(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config")
+	if err := os.WriteFile(path, []byte("font-size = 12\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	cfg, err := Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cfg.Set("font-size", "14")
+	cfg.Set("theme", "Dracula")
+	cfg.Set("theme", "Dracula") // no change, not journaled
+	if err := cfg.Save(); err != nil {
+		t.Fatal(err)
+	}
+
+	if key, ok := cfg.Undo(); !ok || key != "theme" {
+		t.Fatalf("Expected to undo theme, got %s %v", key, ok)
+	}
+	if key, ok := cfg.Undo(); !ok || key != "font-size" {
+		t.Fatalf("Expected to undo font-size, got %s %v", key, ok)
+	}
+	if _, ok := cfg.Undo(); ok {
+		t.Error("Expected nothing left to undo")
+	}
+	if err := cfg.Save(); err != nil {
+		t.Fatal(err)
+	}
+	if data, _ := os.ReadFile(path); string(data) != "font-size = 12\n" {
+		t.Errorf("Expected original file after undo, got %q", data)
+	}
+
+	if key, ok := cfg.Redo(); !ok || key != "font-size" {
+		t.Fatalf("Expected to redo font-size, got %s %v", key, ok)
+	}
+	if got := cfg.Get("font-size"); got != "14" {
+		t.Errorf("Expected 14 after redo, got %s", got)
+	}
+	cfg.Add("keybind", "ctrl+a=new_tab")
+	if cfg.CanRedo() {
+		t.Error("Expected a new edit to clear the redo stack")
+	}
+}
+
+func TestUnset(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config")
+	content := "# Theme\ntheme = Dracula\n\n# Size\nfont-size = 12\nfont-size = 13\n"
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	cfg, err := Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cfg.Unset("font-size")
+	if err := cfg.Save(); err != nil {
+		t.Fatal(err)
+	}
+	if data, _ := os.ReadFile(path); string(data) != "# Theme\ntheme = Dracula\n\n# Size\n" {
+		t.Errorf("Unexpected content after Unset: %q", data)
+	}
+	if got := cfg.Get("font-size"); got != "" {
+		t.Errorf("Expected no value after Unset, got %s", got)
+	}
+}
+
+func TestSetRejectsLineBreaks(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config")
+	content := "font-size = 12\nkeybind = ctrl+t=new_tab\n"
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	cfg, err := Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for name, err := range map[string]error{
+		"Set":           cfg.Set("font-size", "13\nfont-size = 14"),
+		"Set empty":     cfg.Set("", "13"),
+		"SetAll":        cfg.SetAll("keybind", []string{"ctrl+t=new_tab", "ctrl+w=close_surface\r"}),
+		"SetAt":         cfg.SetAt("keybind", 0, "ctrl+t=new_tab\n"),
+		"Add":           cfg.Add("keybind", "ctrl+q=quit\r\n"),
+		"Add empty":     cfg.Add(" ", "ctrl+q=quit"),
+		"NewEntry":      func() error { _, err := NewEntry("font-size", "1\n2"); return err }(),
+		"Line.SetValue": ParseDocument([]byte("font-size = 12\n")).Lines[0].SetValue("1\r2"),
+	} {
+		if err == nil {
+			t.Errorf("Expected %s to return an error", name)
+		}
+	}
+	if cfg.CanUndo() {
+		t.Error("Expected rejected values to leave no undo step")
+	}
+	if err := cfg.Save(); err != nil {
+		t.Fatal(err)
+	}
+	if data, _ := os.ReadFile(path); string(data) != content {
+		t.Errorf("Expected the file to be unchanged, got %q", data)
+	}
+}
+
+func TestSaveMergesExternalChanges(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config")
+	if err := os.WriteFile(path, []byte("font-size = 12\ntheme = Dracula\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	cfg, err := Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Another program changes theme while we change font-size
+	if err := os.WriteFile(path, []byte("# edited in vim\nfont-size = 12\ntheme = Nord\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if changed, err := cfg.ExternalChanges(); err != nil || len(changed) != 1 {
+		t.Errorf("Expected the external change to be noticed, got %v %v", changed, err)
+	}
+	cfg.Set("font-size", "14")
+	if err := cfg.Save(); err != nil {
+		t.Fatal(err)
+	}
+	if data, _ := os.ReadFile(path); string(data) != "# edited in vim\nfont-size = 14\ntheme = Nord\n" {
+		t.Errorf("Unexpected merge result: %q", data)
+	}
+
+	// Both sides change font-size
+	if err := os.WriteFile(path, []byte("font-size = 16\ntheme = Nord\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	cfg.Set("font-size", "18")
+	var conflictErr *ConflictError
+	if err := cfg.Save(); !errors.As(err, &conflictErr) {
+		t.Fatalf("Expected a ConflictError, got %v", err)
+	}
+	if cf := conflictErr.Conflicts[0]; cf.Key != "font-size" || cf.Mine[0] != "18" || cf.Theirs[0] != "16" {
+		t.Errorf("Unexpected conflict: %+v", cf)
+	}
+	if data, _ := os.ReadFile(path); string(data) != "font-size = 16\ntheme = Nord\n" {
+		t.Errorf("Expected conflicting file to be left alone, got %q", data)
+	}
+
+	if err := cfg.Resolve(path, "font-size", false); err != nil {
+		t.Fatal(err)
+	}
+	cfg.Set("theme", "Dracula")
+	if err := cfg.Save(); err != nil {
+		t.Fatal(err)
+	}
+	if data, _ := os.ReadFile(path); string(data) != "font-size = 16\ntheme = Dracula\n" {
+		t.Errorf("Unexpected content after resolving: %q", data)
+	}
+}
+
+func TestDiagnostics(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config")
+	long := strings.Repeat("x", 70*1024)
+	content := "\ufefffont-family = \"JetBrains Mono\"\r\n" +
+		"this line has no equals\r\n" +
+		"  = orphan\r\n" +
+		"title = \"unterminated\r\n" +
+		"no-such-option = 1\r\n" +
+		"command = " + long + "\r\n"
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg, err := Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := cfg.Get("font-family"); got != "JetBrains Mono" {
+		t.Errorf("Expected unquoted value after BOM, got %q", got)
+	}
+	if got := cfg.Get("command"); got != long {
+		t.Errorf("Expected long line to load, got %d bytes", len(got))
+	}
+
+	options := []schema.Option{{Key: "font-family"}, {Key: "title"}, {Key: "command"}}
+	var got []string
+	for _, d := range cfg.Diagnose(options, nil) {
+		got = append(got, fmt.Sprintf("%d:%d %s", d.Line, d.Column, d.Severity))
+	}
+	expected := []string{"0:0 warning", "0:0 info", "2:1 error", "3:3 error", "4:9 warning", "5:1 error"}
+	if strings.Join(got, ", ") != strings.Join(expected, ", ") {
+		t.Errorf("Expected diagnostics %v, got %v", expected, got)
+	}
+
+	// Quotes and the BOM survive an edit
+	cfg.Set("font-family", "Fira Code")
+	if err := cfg.Save(); err != nil {
+		t.Fatal(err)
+	}
+	data, _ := os.ReadFile(path)
+	if !strings.HasPrefix(string(data), "\ufefffont-family = \"Fira Code\"\r\n") {
+		t.Errorf("Unexpected content after save: %q", data[:40])
+	}
+}
+
+func TestDiagnoseValues(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config")
+	content := "background = #12\nbackground = 282a36\npalette = 0=#000000\npalette = 300=#ffffff\nkeybind = a=b\nkeybind = c=d\n"
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	cfg, err := Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	options := []schema.Option{{Key: "background"}, {Key: "palette"}, {Key: "keybind"}}
+	var got []string
+	for _, d := range cfg.Diagnose(options, nil) {
+		got = append(got, fmt.Sprintf("%d %s", d.Line, d.Rule))
+	}
+	expected := []string{"1 invalid-value", "1 duplicate-key", "4 invalid-value"}
+	if strings.Join(got, ", ") != strings.Join(expected, ", ") {
+		t.Errorf("Expected diagnostics %v, got %v", expected, got)
+	}
+}
+
+func TestDiagnoseInferredTypes(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config")
+	content := "cursor-style = beam\ncursor-style = bar\nfont-thicken = yes\nwindow-padding-x = 2,4\nwindow-padding-x = 2.5\n" +
+		"resize-overlay-duration = 1h30m\nresize-overlay-duration = soon\nbackground-opacity = 0.9\n"
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	cfg, err := Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	options := []schema.Option{
+		{Key: "cursor-style", DefaultValue: "block", Description: "The style of the cursor.\n\nValid values are:\n\n  * `block`\n  * `bar`\n  * `underline`"},
+		{Key: "font-thicken", DefaultValue: "false"},
+		{Key: "window-padding-x", DefaultValue: "2"},
+		{Key: "resize-overlay-duration", DefaultValue: "750ms"},
+		{Key: "background-opacity", DefaultValue: "1"},
+	}
+	var got []string
+	for _, d := range cfg.Diagnose(options, nil) {
+		if d.Rule == RuleInvalidValue {
+			got = append(got, fmt.Sprint(d.Line))
+		}
+	}
+	expected := []string{"1", "3", "5", "7"}
+	if strings.Join(got, ", ") != strings.Join(expected, ", ") {
+		t.Errorf("Expected invalid values on lines %v, got %v", expected, got)
+	}
+}
+
+func TestDiagnoseDeprecated(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config")
+	content := "background-blur-radius = 20\nold-option = 1\nwindow-vsync = false\nno-such-option = 1\n"
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	cfg, err := Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	options := []schema.Option{
+		{Key: "old-option", DefaultValue: "0", Description: "Does something.\n\nThis option is deprecated and has no effect."},
+		{Key: "window-vsync", DefaultValue: "true", Description: "Synchronize rendering. This is only supported on macOS."},
+	}
+	var got []string
+	for _, d := range cfg.Diagnose(options, nil) {
+		got = append(got, fmt.Sprintf("%d %s", d.Line, d.Rule))
+	}
+	expected := []string{"1 deprecated", "2 deprecated", "4 unknown-key"}
+	if strings.Join(got, ", ") != strings.Join(expected, ", ") {
+		t.Errorf("Expected diagnostics %v, got %v", expected, got)
+	}
+}
+
+func TestCompare(t *testing.T) {
+	options := []schema.Option{
+		{Key: "font-size", DefaultValue: "13"},
+		{Key: "theme"},
+		{Key: "keybind"},
+		{Key: "background", DefaultValue: "282c34"},
+		{Key: "foreground", DefaultValue: "ffffff"},
+	}
+	from := map[string][]string{
+		"font-size":  {"12", "14"},
+		"keybind":    {"a=b"},
+		"background": {"#282C34"},
+	}
+	to := map[string][]string{
+		"font-size":  {"14.0"},
+		"keybind":    {"a=b", "c=d"},
+		"theme":      {"Nord"},
+		"foreground": {"#fff"},
+	}
+
+	var got []string
+	for _, c := range Compare(from, to, options) {
+		got = append(got, fmt.Sprintf("%s %v %v", c.Key, c.From, c.To))
+	}
+	// Only the last font-size counts, and values spelled differently are
+	// the same: background and foreground equal their defaults
+	expected := []string{"theme [] [Nord]", "keybind [a=b] [a=b c=d]"}
+	if strings.Join(got, ", ") != strings.Join(expected, ", ") {
+		t.Errorf("Expected changes %v, got %v", expected, got)
+	}
+}
+
+func TestAffected(t *testing.T) {
+	changes := []schema.OptionChange{
+		{Key: "cursor-style", Kind: schema.ChangeDefault, From: "block", To: "bar"},
+		{Key: "window-subtitle", Kind: schema.ChangeAdded, To: "false"},
+		{Key: "gtk-adwaita", Kind: schema.ChangeRemoved, From: "true"},
+	}
+
+	values := map[string][]string{
+		"font-size":       {"14"},
+		"cursor-style":    {"underline"},
+		"gtk-adwaita":     {"false"},
+		"window-subtitle": {"false"},
+	}
+	affected := Affected(values, changes)
+	if strings.Join(affected, ", ") != "cursor-style, gtk-adwaita" {
+		t.Errorf("Expected cursor-style and gtk-adwaita to be affected, got %v", affected)
+	}
+}
+
+func TestDiagnoseThemeValue(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config")
+	content := "theme = light:A,dark:B\ntheme = light:A\ntheme = dark:A,dark:B\n"
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	cfg, err := Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []string
+	for _, d := range cfg.Diagnose([]schema.Option{{Key: "theme"}}, nil) {
+		if d.Rule == RuleInvalidValue {
+			got = append(got, fmt.Sprint(d.Line))
+		}
+	}
+	if strings.Join(got, ", ") != "2, 3" {
+		t.Errorf("Expected invalid theme values on lines 2 and 3, got %v", got)
+	}
+}
+
+func TestDiagnoseKeybindConflicts(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config")
+	content := "keybind = ctrl+a=new_tab\nkeybind = control+A=new_window\nkeybind = ctrl+b>n=new_split:right\n" +
+		"keybind = ctrl+b=close_surface\nkeybind = super+t=new_window\nkeybind = super+c=copy_to_clipboard\n" +
+		"keybind = super+w>x=close_tab\nkeybind = super+q=unbind\nkeybind = clear\nkeybind = super+t=new_tab\n"
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	cfg, err := Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var defaults []schema.Keybind
+	for _, value := range []string{"super+t=new_tab", "super+c=copy_to_clipboard", "super+w=close_surface", "super+q=quit"} {
+		kb, _ := schema.ParseKeybind(value)
+		defaults = append(defaults, kb)
+	}
+	var got []string
+	for _, d := range cfg.Diagnose([]schema.Option{{Key: "keybind"}}, defaults) {
+		got = append(got, fmt.Sprintf("%d %s %s", d.Line, d.Severity, d.Rule))
+	}
+	expected := []string{
+		"2 warning keybind-duplicate",
+		"4 warning keybind-prefix",
+		"5 info keybind-shadowed",
+		"7 warning keybind-prefix",
+	}
+	if strings.Join(got, ", ") != strings.Join(expected, ", ") {
+		t.Errorf("Expected diagnostics %v, got %v", expected, got)
+	}
+}
+
+// fakeGhostty puts a ghostty on PATH whose +validate-config rejects every
+// value "bad", printing problems the way Ghostty does
+func fakeGhostty(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("needs a shell script")
+	}
+	dir := t.TempDir()
+	script := `#!/bin/sh
+path="${2#--config-file=}"
+out=$(awk -v f="$path" '$NF == "bad" { print f ":" NR ":" $1 ": invalid value \"bad\"" }' "$path")
+[ -z "$out" ] && exit 0
+echo "$out"
+exit 1
+`
+	if err := os.WriteFile(filepath.Join(dir, "ghostty"), []byte(script), 0755); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("PATH", dir+string(os.PathListSeparator)+os.Getenv("PATH"))
+}
+
+func TestValidateEdit(t *testing.T) {
+	fakeGhostty(t)
+	path := filepath.Join(t.TempDir(), "config")
+	if err := os.WriteFile(path, []byte("font-size = bad\nkeybind = a=new_tab\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	cfg, err := Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// A problem the file already had is not blamed on an edit
+	depth := cfg.UndoDepth()
+	cfg.Add("keybind", "b=new_window")
+	if problems, err := cfg.ValidateEdit("keybind", depth); err != nil || len(problems) != 0 {
+		t.Errorf("Expected no problems, got %v, %v", problems, err)
+	}
+	depth = cfg.UndoDepth()
+	cfg.Set("theme", "Dracula")
+	cfg.Set("font-size", "bad")
+	if problems, err := cfg.ValidateEdit("font-size", depth+1); err != nil || len(problems) != 0 {
+		t.Errorf("Expected an edit that changed nothing to pass, got %v, %v", problems, err)
+	}
+
+	depth = cfg.UndoDepth()
+	cfg.Add("keybind", "bad")
+	problems, err := cfg.ValidateEdit("keybind", depth)
+	if err != nil || len(problems) != 1 || problems[0].Line != 4 {
+		t.Fatalf("Expected the new keybind to be rejected, got %v, %v", problems, err)
+	}
+	if got := cfg.GetAll("keybind"); len(got) != 3 {
+		t.Errorf("Expected ValidateEdit to keep the edit, got %v", got)
+	}
+}
@@ -0,0 +1,213 @@
+package config
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/otiai10/ghostconfig/internal/schema"
+)
+
+// Severity tells how serious a diagnostic is
+type Severity string
+
+const (
+	SeverityError   Severity = "error"
+	SeverityWarning Severity = "warning"
+	SeverityInfo    Severity = "info"
+)
+
+// Rules name the kinds of problems Diagnose finds
+const (
+	RuleMalformedLine     = "malformed-line"
+	RuleMissingKey        = "missing-key"
+	RuleUnterminatedQuote = "unterminated-quote"
+	RuleBOM               = "byte-order-mark"
+	RuleLineEndings       = "line-endings"
+	RuleInclude           = "include"
+	RuleUnknownKey        = "unknown-key"
+	RuleDuplicateKey      = "duplicate-key"
+	RuleInvalidValue      = "invalid-value"
+	RuleDeprecated        = "deprecated"
+	RuleKeybindDuplicate  = "keybind-duplicate"
+	RuleKeybindPrefix     = "keybind-prefix"
+	RuleKeybindShadowed   = "keybind-shadowed"
+	RuleGhostty           = "ghostty" // reported by ghostty +validate-config
+)
+
+// Diagnostic is a problem found in a config file.
+// Line and Column are 1-based; Line is 0 for problems with the whole file.
+type Diagnostic struct {
+	File     string   `json:"file"`
+	Line     int      `json:"line"`
+	Column   int      `json:"column"`
+	Severity Severity `json:"severity"`
+	Rule     string   `json:"rule"`
+	Message  string   `json:"message"`
+}
+
+func (d Diagnostic) String() string {
+	pos := d.File
+	if d.Line > 0 {
+		pos = fmt.Sprintf("%s:%d:%d", d.File, d.Line, d.Column)
+	}
+	return fmt.Sprintf("%s: %s: %s", pos, d.Severity, d.Message)
+}
+
+// diagnose checks the lines of a parsed config file
+func diagnose(file string, doc *Document) []Diagnostic {
+	var diags []Diagnostic
+	add := func(line, column int, severity Severity, rule, format string, args ...any) {
+		diags = append(diags, Diagnostic{
+			File:     file,
+			Line:     line,
+			Column:   column,
+			Severity: severity,
+			Rule:     rule,
+			Message:  fmt.Sprintf(format, args...),
+		})
+	}
+
+	if doc.BOM {
+		add(0, 0, SeverityWarning, RuleBOM, "file starts with a UTF-8 byte order mark, which Ghostty may read as part of the first key")
+	}
+
+	crlf, lf := 0, 0
+	for i, l := range doc.Lines {
+		switch l.EOL {
+		case "\r\n":
+			crlf++
+		case "\n":
+			lf++
+		}
+
+		column := len(l.Raw) - len(strings.TrimLeft(l.Raw, " \t")) + 1
+		switch {
+		case l.Kind == LineUnknown:
+			add(i+1, column, SeverityError, RuleMalformedLine, "missing \"=\"; Ghostty ignores this line")
+		case l.Kind == LineEntry && l.Key == "":
+			add(i+1, column, SeverityError, RuleMissingKey, "missing key before \"=\"")
+		case l.Kind == LineEntry && !l.quoted && strings.HasPrefix(l.Value, `"`):
+			add(i+1, l.valueStart+1, SeverityWarning, RuleUnterminatedQuote, "unterminated quote; the quote is read as part of the value")
+		}
+	}
+
+	switch {
+	case crlf > 0 && lf > 0:
+		add(0, 0, SeverityWarning, RuleLineEndings, "mixed line endings (%d CRLF, %d LF)", crlf, lf)
+	case crlf > 0:
+		add(0, 0, SeverityInfo, RuleLineEndings, "file uses CRLF line endings; they are kept when saving")
+	}
+	return diags
+}
+
+// Diagnose returns the problems found in the loaded files, including
+// keys that are not among options, deprecated keys, values that do not
+// fit their option (see schema.CheckValue), keys that take one value
+// but are set more than once and keybinds that replace another binding
+// or one of defaults (see schema.KeybindConflicts). Without options, keys
+// and values are not checked; without defaults, keybinds are only checked
+// against each other. Diagnostics are ordered by file, line and column.
+func (c *Config) Diagnose(options []schema.Option, defaults []schema.Keybind) []Diagnostic {
+	diags := slices.Clone(c.Diagnostics)
+	at := func(src Source, severity Severity, rule, format string, args ...any) {
+		diags = append(diags, Diagnostic{
+			File:     src.File,
+			Line:     src.Line,
+			Column:   1,
+			Severity: severity,
+			Rule:     rule,
+			Message:  fmt.Sprintf(format, args...),
+		})
+	}
+
+	known := make(map[string]schema.Option, len(options))
+	for _, opt := range options {
+		known[opt.Key] = opt
+	}
+	for key, srcs := range c.Sources {
+		values := c.Values[key]
+		opt, ok := known[key]
+		for i, src := range srcs {
+			if src.Line == 0 || i >= len(values) {
+				continue
+			}
+			switch {
+			case len(options) == 0:
+			case !ok:
+				// Deprecated keys Ghostty no longer lists are known to overlay.json
+				if note := (schema.Option{Key: key}).Metadata().Deprecated; note != "" {
+					at(src, SeverityWarning, RuleDeprecated, "%s is deprecated: %s", key, note)
+				} else {
+					at(src, SeverityError, RuleUnknownKey, "unknown option %q; Ghostty ignores it", key)
+				}
+				continue
+			default:
+				if err := schema.CheckValue(opt, values[i]); err != nil {
+					at(src, SeverityError, RuleInvalidValue, "%s: %v", key, err)
+				}
+				if note := opt.Metadata().Deprecated; note != "" {
+					at(src, SeverityWarning, RuleDeprecated, "%s is deprecated: %s", key, note)
+				}
+			}
+			if !IsRepeatable(key) && i < len(srcs)-1 {
+				at(src, SeverityWarning, RuleDuplicateKey, "%s is set again at %s, which overrides this value", key, srcs[len(srcs)-1])
+			}
+		}
+	}
+
+	diags = append(diags, c.keybindConflicts(defaults)...)
+
+	sort.SliceStable(diags, func(i, j int) bool {
+		a, b := diags[i], diags[j]
+		if a.File != b.File {
+			return slices.Index(c.Files, a.File) < slices.Index(c.Files, b.File)
+		}
+		if a.Line != b.Line {
+			return a.Line < b.Line
+		}
+		return a.Column < b.Column
+	})
+	return diags
+}
+
+// keybindConflicts reports the keybinds that stop another binding from
+// working. Values that cannot be parsed are left to CheckValue.
+func (c *Config) keybindConflicts(defaults []schema.Keybind) []Diagnostic {
+	var binds []schema.Keybind
+	var srcs []Source
+	for i, value := range c.Values["keybind"] {
+		kb, err := schema.ParseKeybind(value)
+		if err != nil || i >= len(c.Sources["keybind"]) {
+			continue
+		}
+		binds = append(binds, kb)
+		srcs = append(srcs, c.Sources["keybind"][i])
+	}
+
+	var diags []Diagnostic
+	for _, conflict := range schema.KeybindConflicts(binds, defaults) {
+		kb, src := binds[conflict.Index], srcs[conflict.Index]
+		if src.Line == 0 {
+			continue
+		}
+		d := Diagnostic{File: src.File, Line: src.Line, Column: 1, Severity: SeverityWarning}
+		switch {
+		case conflict.Kind == schema.ConflictShadowed:
+			d.Severity, d.Rule = SeverityInfo, RuleKeybindShadowed
+			d.Message = fmt.Sprintf("keybind %s replaces the default %s", kb.Trigger(), conflict.Default)
+		case conflict.Other < 0:
+			d.Rule = RuleKeybindPrefix
+			d.Message = fmt.Sprintf("keybind %s and the default %s share a key sequence prefix; the default stops working", kb.Trigger(), conflict.Default)
+		case conflict.Kind == schema.ConflictDuplicate:
+			d.Rule = RuleKeybindDuplicate
+			d.Message = fmt.Sprintf("keybind %s is already bound to %s at %s; this binding replaces it", kb.Trigger(), binds[conflict.Other].Action, srcs[conflict.Other])
+		default:
+			d.Rule = RuleKeybindPrefix
+			d.Message = fmt.Sprintf("keybind %s and %s at %s share a key sequence prefix; this binding replaces it", kb.Trigger(), binds[conflict.Other].Trigger(), srcs[conflict.Other])
+		}
+		diags = append(diags, d)
+	}
+	return diags
+}
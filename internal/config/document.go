@@ -0,0 +1,522 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/otiai10/ghostconfig/internal/schema"
+)
+
+// LineKind classifies a line of a config file
+type LineKind int
+
+const (
+	LineBlank LineKind = iota
+	LineComment
+	LineEntry
+	LineUnknown
+)
+
+// Line is a single line of a config file.
+// Raw and EOL hold the original bytes, so an untouched line is written back as is.
+type Line struct {
+	Kind  LineKind
+	Raw   string
+	EOL   string
+	Key   string
+	Value string
+
+	// Byte range of the value inside Raw, used to edit it in place.
+	// For quoted values the range excludes the quotes.
+	valueStart int
+	valueEnd   int
+	quoted     bool
+}
+
+// SetValue replaces the value of an entry line, keeping the key,
+// the spacing around "=", quotes and any trailing whitespace.
+func (l *Line) SetValue(value string) error {
+	if l.Kind != LineEntry {
+		return nil
+	}
+	if err := CheckEntry(l.Key, value); err != nil {
+		return err
+	}
+	if l.Value == value {
+		return nil
+	}
+	if !l.quoted && needsQuotes(value) {
+		l.Raw = l.Raw[:l.valueStart] + `"` + value + `"` + l.Raw[l.valueEnd:]
+		l.valueStart++
+		l.valueEnd = l.valueStart + len(value)
+		l.Value, l.quoted = value, true
+		return nil
+	}
+	l.Raw = l.Raw[:l.valueStart] + value + l.Raw[l.valueEnd:]
+	l.valueEnd = l.valueStart + len(value)
+	l.Value = value
+	return nil
+}
+
+// NewEntry creates an entry line in the canonical "key = value" form.
+// Values with leading or trailing whitespace are quoted.
+func NewEntry(key, value string) (*Line, error) {
+	if err := CheckEntry(key, value); err != nil {
+		return nil, err
+	}
+	if needsQuotes(value) {
+		value = `"` + value + `"`
+	}
+	return parseLine(key+" = "+value, "\n"), nil
+}
+
+// CheckEntry reports an error if key and value cannot be written as one
+// line of a config file: the key is empty or the value has a line break
+func CheckEntry(key, value string) error {
+	if strings.TrimSpace(key) == "" {
+		return errors.New("empty key")
+	}
+	if strings.ContainsAny(value, "\r\n") {
+		return fmt.Errorf("value of %s must not contain a line break", key)
+	}
+	return nil
+}
+
+// needsQuotes reports whether value only survives a round trip when quoted
+func needsQuotes(value string) bool {
+	return value != strings.TrimSpace(value) || isQuoted(value)
+}
+
+// isQuoted reports whether value is wrapped in double quotes,
+// which Ghostty strips
+func isQuoted(value string) bool {
+	return len(value) >= 2 && value[0] == '"' && value[len(value)-1] == '"'
+}
+
+func parseLine(raw, eol string) *Line {
+	l := &Line{Raw: raw, EOL: eol}
+	trimmed := strings.TrimSpace(raw)
+	switch {
+	case trimmed == "":
+		l.Kind = LineBlank
+	case strings.HasPrefix(trimmed, "#"):
+		l.Kind = LineComment
+	default:
+		eq := strings.Index(raw, "=")
+		if eq < 0 {
+			l.Kind = LineUnknown
+			break
+		}
+		l.Kind = LineEntry
+		l.Key = strings.TrimSpace(raw[:eq])
+		rest := raw[eq+1:]
+		value := strings.TrimSpace(rest)
+		l.valueStart = eq + 1 + len(rest) - len(strings.TrimLeft(rest, " \t"))
+		l.valueEnd = l.valueStart + len(value)
+		if isQuoted(value) {
+			value = value[1 : len(value)-1]
+			l.valueStart++
+			l.valueEnd--
+			l.quoted = true
+		}
+		l.Value = value
+	}
+	return l
+}
+
+// Document is a lossless representation of a config file.
+// Blank lines, comments, spacing, line endings and lines that are not
+// understood are all kept, so Bytes reproduces the original file exactly.
+type Document struct {
+	Lines []*Line
+
+	// BOM is set if the file starts with a UTF-8 byte order mark,
+	// which is kept but not part of the first line
+	BOM bool
+
+	// Line ending used for inserted lines
+	eol string
+}
+
+// utf8BOM is the UTF-8 encoded byte order mark
+const utf8BOM = "\ufeff"
+
+// ParseDocument parses the contents of a config file
+func ParseDocument(data []byte) *Document {
+	doc := &Document{eol: "\n"}
+	text := string(data)
+	if strings.HasPrefix(text, utf8BOM) {
+		doc.BOM = true
+		text = text[len(utf8BOM):]
+	}
+	if strings.Contains(text, "\r\n") {
+		doc.eol = "\r\n"
+	}
+
+	for text != "" {
+		raw, eol := text, ""
+		if i := strings.IndexByte(text, '\n'); i >= 0 {
+			raw, eol = text[:i], "\n"
+			text = text[i+1:]
+		} else {
+			text = ""
+		}
+		if strings.HasSuffix(raw, "\r") && eol != "" {
+			raw, eol = raw[:len(raw)-1], "\r\n"
+		}
+		doc.Lines = append(doc.Lines, parseLine(raw, eol))
+	}
+	return doc
+}
+
+// Bytes serializes the document
+func (d *Document) Bytes() []byte {
+	var b strings.Builder
+	if d.BOM {
+		b.WriteString(utf8BOM)
+	}
+	for _, l := range d.Lines {
+		b.WriteString(l.Raw)
+		b.WriteString(l.EOL)
+	}
+	return []byte(b.String())
+}
+
+// Values returns every value of every key in file order
+func (d *Document) Values() map[string][]string {
+	values := make(map[string][]string)
+	for _, l := range d.Lines {
+		if l.Kind == LineEntry && l.Key != "" {
+			values[l.Key] = append(values[l.Key], l.Value)
+		}
+	}
+	return values
+}
+
+// Insert inserts lines before index. An index of len(Lines) appends.
+func (d *Document) Insert(index int, lines ...*Line) {
+	for _, l := range lines {
+		l.EOL = d.eol
+	}
+	// The line before the insertion point may be the last line without a newline
+	if index > 0 && d.Lines[index-1].EOL == "" {
+		d.Lines[index-1].EOL = d.eol
+		if index == len(d.Lines) {
+			lines[len(lines)-1].EOL = ""
+		}
+	}
+	d.Lines = append(d.Lines[:index], append(lines, d.Lines[index:]...)...)
+}
+
+// Remove removes the line at index
+func (d *Document) Remove(index int) {
+	last := index == len(d.Lines)-1
+	eol := d.Lines[index].EOL
+	d.Lines = append(d.Lines[:index], d.Lines[index+1:]...)
+	// Keep a missing final newline missing
+	if last && index > 0 {
+		d.Lines[index-1].EOL = eol
+	}
+}
+
+// LeadingComments returns the index of the first line of the comment block
+// attached to the entry at index, or index itself if there is none. A
+// comment is attached if it sits directly above the entry, with no blank
+// line between them, and documents that entry alone: decorated headers
+// such as "# ==== Keys ====" and comments above the first of several
+// consecutive entries introduce what follows them and are not attached.
+// Apply removes and moves attached comments together with their entry.
+func (d *Document) LeadingComments(index int) int {
+	start := index
+	for start > 0 && d.Lines[start-1].Kind == LineComment && !isDecorated(d.Lines[start-1]) {
+		start--
+	}
+	if start == index {
+		return index
+	}
+	groupStart := start == 0 || d.Lines[start-1].Kind != LineEntry
+	if groupStart && index+1 < len(d.Lines) && d.Lines[index+1].Kind == LineEntry {
+		return index
+	}
+	return start
+}
+
+// isDecorated reports whether a comment is a header that starts with a
+// run of a character such as "=" or "-", like "# --- Font ---"
+func isDecorated(l *Line) bool {
+	text := strings.TrimSpace(strings.TrimLeft(strings.TrimSpace(l.Raw), "#"))
+	if len(text) < 3 || !strings.ContainsRune("=-*#~_+", rune(text[0])) {
+		return false
+	}
+	return text[1] == text[0] && text[2] == text[0]
+}
+
+// removeEntry removes the entry at index and the comments attached to it,
+// which start at start (see LeadingComments). A blank line that is left
+// doubled or at the end of the file by removing comments goes too.
+func (d *Document) removeEntry(start, index int) {
+	for i := index; i >= start; i-- {
+		d.Remove(i)
+	}
+	if start < index && start > 0 && d.Lines[start-1].Kind == LineBlank &&
+		(start == len(d.Lines) || d.Lines[start].Kind == LineBlank) {
+		d.Remove(start - 1)
+	}
+}
+
+// Apply updates the document to hold the given values.
+// Lines whose values are no longer wanted are removed, and lines whose
+// values were reordered are moved, together with the comments attached
+// to them (see LeadingComments); other comments stay where they are.
+// Then the n-th remaining line of a key receives the n-th value and is
+// only rewritten if that value changed, and extra values are inserted
+// after the last line of the key. Keys not in the document are placed
+// next to keys of the same category (see schema.ExtractSection), in
+// category order and then by name, so the result is deterministic.
+// Keys missing from values are left untouched. If a value cannot be
+// written (see CheckEntry), Apply returns an error and changes nothing.
+func (d *Document) Apply(values map[string][]string) error {
+	for key, want := range values {
+		for _, value := range want {
+			if err := CheckEntry(key, value); err != nil {
+				return err
+			}
+		}
+	}
+
+	removed := make(map[string]bool)
+	for key, want := range values {
+		if d.arrange(key, want) {
+			removed[schema.ExtractSection(key)] = true
+		}
+	}
+
+	seen := make(map[string]int)
+	for i := 0; i < len(d.Lines); i++ {
+		l := d.Lines[i]
+		if l.Kind != LineEntry {
+			continue
+		}
+		want, managed := values[l.Key]
+		if !managed {
+			continue
+		}
+
+		// arrange left no more lines than values
+		n := seen[l.Key]
+		seen[l.Key]++
+		l.SetValue(want[n]) // checked above
+
+		if d.lastEntry(l.Key) == i && n+1 < len(want) {
+			var extra []*Line
+			for _, value := range want[n+1:] {
+				line, _ := NewEntry(l.Key, value)
+				extra = append(extra, line)
+			}
+			d.Insert(i+1, extra...)
+			seen[l.Key] = len(want)
+			i += len(extra)
+		}
+	}
+
+	for category := range removed {
+		d.dropEmptyHeader(category)
+	}
+
+	// Place keys that are not in the document yet by category
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		if _, ok := seen[key]; !ok && len(values[key]) > 0 {
+			keys = append(keys, key)
+		}
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		ci, cj := schema.CategoryIndex(schema.ExtractSection(keys[i])), schema.CategoryIndex(schema.ExtractSection(keys[j]))
+		if ci != cj {
+			return ci < cj
+		}
+		return keys[i] < keys[j]
+	})
+	for _, key := range keys {
+		lines := make([]*Line, 0, len(values[key]))
+		for _, value := range values[key] {
+			line, _ := NewEntry(key, value)
+			lines = append(lines, line)
+		}
+		d.Insert(d.placeFor(schema.ExtractSection(key)), lines...)
+	}
+	return nil
+}
+
+// entries returns the indexes of the lines of key
+func (d *Document) entries(key string) []int {
+	var indexes []int
+	for i, l := range d.Lines {
+		if l.Kind == LineEntry && l.Key == key {
+			indexes = append(indexes, i)
+		}
+	}
+	return indexes
+}
+
+// arrange prepares the lines of key for the values want. Lines whose
+// values are not wanted are removed while there are more lines than
+// values, and if the lines hold the wanted values in another order, the
+// lines are moved into that order. Comments go with their lines.
+// It reports whether lines were removed.
+func (d *Document) arrange(key string, want []string) (removed bool) {
+	indexes := d.entries(key)
+	if len(indexes) == 0 {
+		return false
+	}
+
+	// Count the wanted values, so a value kept once is not kept twice
+	wanted := make(map[string]int, len(want))
+	for _, v := range want {
+		wanted[v]++
+	}
+	kept := make([]bool, len(indexes))
+	for i, index := range indexes {
+		if v := d.Lines[index].Value; wanted[v] > 0 {
+			wanted[v]--
+			kept[i] = true
+		}
+	}
+	// Comments are judged before anything is removed, as removing an
+	// entry can make a group comment look attached to the one left
+	starts := make([]int, len(indexes))
+	for i, index := range indexes {
+		starts[i] = d.LeadingComments(index)
+	}
+	extra := len(indexes) - len(want)
+	for i := len(indexes) - 1; i >= 0 && extra > 0; i-- {
+		if !kept[i] {
+			d.removeEntry(starts[i], indexes[i])
+			extra--
+			removed = true
+		}
+	}
+	if removed {
+		indexes = d.entries(key)
+	}
+
+	// Reordered values move their lines
+	if len(indexes) != len(want) {
+		return removed
+	}
+	order := make([]int, len(want)) // order[n] is the line that gets want[n]
+	used := make([]bool, len(indexes))
+	moved := false
+	for n, v := range want {
+		order[n] = -1
+		for i, index := range indexes {
+			if !used[i] && d.Lines[index].Value == v {
+				order[n], used[i] = i, true
+				break
+			}
+		}
+		if order[n] < 0 {
+			return removed // values changed, not only their order
+		}
+		moved = moved || order[n] != n
+	}
+	if !moved {
+		return removed
+	}
+
+	// A missing final newline stays at the end of the file
+	last := d.Lines[len(d.Lines)-1]
+	eol := last.EOL
+	last.EOL = d.eol
+	blocks := make([][]*Line, len(indexes))
+	starts = make([]int, len(indexes))
+	for i, index := range indexes {
+		starts[i] = d.LeadingComments(index)
+		blocks[i] = d.Lines[starts[i] : index+1]
+	}
+	lines := make([]*Line, 0, len(d.Lines))
+	pos := 0
+	for n, index := range indexes {
+		lines = append(lines, d.Lines[pos:starts[n]]...)
+		lines = append(lines, blocks[order[n]]...)
+		pos = index + 1
+	}
+	lines = append(lines, d.Lines[pos:]...)
+	d.Lines = lines
+	d.Lines[len(d.Lines)-1].EOL = eol
+	return removed
+}
+
+// placeFor returns where a new key of category is inserted: after the last
+// entry of the same category, below the category's header, or at the end
+// of the document under a new header, which is then added.
+func (d *Document) placeFor(category string) int {
+	header := categoryHeader(category)
+	headerAt := -1
+	for i := len(d.Lines) - 1; i >= 0; i-- {
+		l := d.Lines[i]
+		if l.Kind == LineEntry && schema.ExtractSection(l.Key) == category {
+			return i + 1
+		}
+		if headerAt < 0 && l.Kind == LineComment && strings.TrimSpace(l.Raw) == header {
+			headerAt = i
+		}
+	}
+	if headerAt >= 0 {
+		return headerAt + 1
+	}
+
+	var lines []*Line
+	if n := len(d.Lines); n > 0 && d.Lines[n-1].Kind != LineBlank {
+		lines = append(lines, parseLine("", ""))
+	}
+	lines = append(lines, parseLine(header, ""))
+	d.Insert(len(d.Lines), lines...)
+	return len(d.Lines)
+}
+
+// dropEmptyHeader removes the header of category, and the blank line above
+// it or, at the top of the file, below it, once no entries are left below it
+func (d *Document) dropEmptyHeader(category string) {
+	header := categoryHeader(category)
+	for i, l := range d.Lines {
+		if l.Kind != LineComment || strings.TrimSpace(l.Raw) != header {
+			continue
+		}
+		j := i + 1
+		for j < len(d.Lines) && d.Lines[j].Kind == LineBlank {
+			j++
+		}
+		if j < len(d.Lines) && !(d.Lines[j].Kind == LineComment && strings.HasPrefix(strings.TrimSpace(d.Lines[j].Raw), "# --- ")) {
+			return
+		}
+		d.Remove(i)
+		switch {
+		case i > 0 && d.Lines[i-1].Kind == LineBlank:
+			d.Remove(i - 1)
+		case i == 0 && len(d.Lines) > 0 && d.Lines[0].Kind == LineBlank:
+			// Nothing above; the blank line below would start the file
+			d.Remove(0)
+		}
+		return
+	}
+}
+
+// categoryHeader returns the comment line introducing a category,
+// such as "# --- Appearance ---". It is not translated, so files read
+// the same whatever the language of the editor.
+func categoryHeader(category string) string {
+	return "# --- " + strings.ToUpper(category[:1]) + category[1:] + " ---"
+}
+
+// lastEntry returns the index of the last line of key
+func (d *Document) lastEntry(key string) int {
+	for i := len(d.Lines) - 1; i >= 0; i-- {
+		if d.Lines[i].Kind == LineEntry && d.Lines[i].Key == key {
+			return i
+		}
+	}
+	return -1
+}
@@ -0,0 +1,202 @@
+package config
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/otiai10/ghostconfig/internal/schema"
+)
+
+func TestDocumentRoundTrip(t *testing.T) {
+	inputs := []string{
+		"",
+		"font-size=12",
+		"# comment\n\n  font-size   =   12  \nthis line has no equals\n\tkeybind=ctrl+a=new_tab\n",
+		"theme = Dracula\r\n# windows\r\nfont-size = 13\r\n",
+	}
+	for _, input := range inputs {
+		if got := string(ParseDocument([]byte(input)).Bytes()); got != input {
+			t.Errorf("Round trip mismatch:\nexpected %q\ngot      %q", input, got)
+		}
+	}
+}
+
+func TestDocumentApply(t *testing.T) {
+	input := "# Font\nfont-size   =   12  \n\nkeybind=a=new_tab\nkeybind=b=close_surface\nunknown line\ntheme = Dracula"
+	doc := ParseDocument([]byte(input))
+
+	doc.Apply(map[string][]string{
+		"font-size":  {"14"},
+		"keybind":    {"a=new_tab"},
+		"theme":      {"Dracula"},
+		"background": {"000000"},
+	})
+
+	expected := "# Font\nfont-size   =   14  \n\nkeybind=a=new_tab\nunknown line\ntheme = Dracula\nbackground = 000000"
+	if got := string(doc.Bytes()); got != expected {
+		t.Errorf("Expected:\n%q\nGot:\n%q", expected, got)
+	}
+}
+
+func TestDocumentApplyPlacesNewKeys(t *testing.T) {
+	input := "theme = Dracula\nfont-size = 12\n\n# --- Shell ---\n"
+	values := map[string][]string{
+		"background":          {"000000"},
+		"font-family":         {"JetBrains Mono"},
+		"window-padding-x":    {"4"},
+		"scrollback-limit":    {"1000"},
+		"cursor-style":        {"bar"},
+		"macos-option-as-alt": {},
+	}
+	expected := "theme = Dracula\nbackground = 000000\ncursor-style = bar\nfont-size = 12\nfont-family = JetBrains Mono\n\n# --- Shell ---\nscrollback-limit = 1000\n\n# --- Window ---\nwindow-padding-x = 4\n"
+
+	// Map iteration order must not leak into the result
+	for range 10 {
+		doc := ParseDocument([]byte(input))
+		doc.Apply(values)
+		if got := string(doc.Bytes()); got != expected {
+			t.Fatalf("Expected:\n%q\nGot:\n%q", expected, got)
+		}
+	}
+
+	// Removing the only entry of a generated section removes its header too
+	doc := ParseDocument([]byte(expected))
+	doc.Apply(map[string][]string{"window-padding-x": {}})
+	if got, want := string(doc.Bytes()), expected[:strings.Index(expected, "\n\n# --- Window")+1]; got != want {
+		t.Errorf("Expected:\n%q\nGot:\n%q", want, got)
+	}
+}
+
+func TestDocumentLeadingComments(t *testing.T) {
+	doc := ParseDocument([]byte("a = 1\n\n# about b\n# more\nb = 2\n"))
+	if got := doc.LeadingComments(4); got != 2 {
+		t.Errorf("Expected comment block to start at 2, got %d", got)
+	}
+	if got := doc.LeadingComments(0); got != 0 {
+		t.Errorf("Expected 0, got %d", got)
+	}
+	doc = ParseDocument([]byte("# --- Font ---\n# about size\nfont-size = 12\n"))
+	if got := doc.LeadingComments(2); got != 1 {
+		t.Errorf("Expected the category header to be left out, got %d", got)
+	}
+}
+
+func TestDocumentApplyRemovesComments(t *testing.T) {
+	input := "# --- Font ---\n# Reading size\nfont-size = 12\n\n" +
+		"# --- Input ---\n# New tab\nkeybind = ctrl+t=new_tab\n\n# Close\n# the tab\nkeybind = ctrl+w=close_surface\n\n" +
+		"# Quit\nkeybind = ctrl+q=quit\n"
+	doc := ParseDocument([]byte(input))
+	doc.Apply(map[string][]string{"keybind": {"ctrl+t=new_tab", "ctrl+q=quit"}})
+	expected := "# --- Font ---\n# Reading size\nfont-size = 12\n\n" +
+		"# --- Input ---\n# New tab\nkeybind = ctrl+t=new_tab\n\n# Quit\nkeybind = ctrl+q=quit\n"
+	if got := string(doc.Bytes()); got != expected {
+		t.Errorf("Expected:\n%q\nGot:\n%q", expected, got)
+	}
+
+	doc.Apply(map[string][]string{"font-size": {}})
+	expected = "# --- Input ---\n# New tab\nkeybind = ctrl+t=new_tab\n\n# Quit\nkeybind = ctrl+q=quit\n"
+	if got := string(doc.Bytes()); got != expected {
+		t.Errorf("Expected:\n%q\nGot:\n%q", expected, got)
+	}
+}
+
+func TestDocumentApplyKeepsGroupComments(t *testing.T) {
+	doc := ParseDocument([]byte("# ==== Appearance ====\ntheme = x\n"))
+	doc.Apply(map[string][]string{"theme": {}})
+	if got, expected := string(doc.Bytes()), "# ==== Appearance ====\n"; got != expected {
+		t.Errorf("Expected:\n%q\nGot:\n%q", expected, got)
+	}
+
+	doc = ParseDocument([]byte("# ==== My keybindings ====\nkeybind = a=new_tab\nkeybind = b=quit\n"))
+	doc.Apply(map[string][]string{"keybind": {"b=quit"}})
+	if got, expected := string(doc.Bytes()), "# ==== My keybindings ====\nkeybind = b=quit\n"; got != expected {
+		t.Errorf("Expected:\n%q\nGot:\n%q", expected, got)
+	}
+
+	doc = ParseDocument([]byte("# Tabs\nkeybind = a=new_tab\nkeybind = b=close_tab\n"))
+	doc.Apply(map[string][]string{"keybind": {"b=close_tab", "a=new_tab"}})
+	if got, expected := string(doc.Bytes()), "# Tabs\nkeybind = b=close_tab\nkeybind = a=new_tab\n"; got != expected {
+		t.Errorf("Expected:\n%q\nGot:\n%q", expected, got)
+	}
+}
+
+func TestDocumentApplyMovesComments(t *testing.T) {
+	// The last line has no newline, which must stay at the end
+	input := "# New tab\nkeybind = ctrl+t=new_tab\n\nfont-size = 12\n# Close\n# the tab\nkeybind = ctrl+w=close_surface\nkeybind = ctrl+q=quit"
+	doc := ParseDocument([]byte(input))
+	doc.Apply(map[string][]string{"keybind": {"ctrl+q=quit", "ctrl+t=new_tab", "ctrl+w=close_surface"}})
+	expected := "keybind = ctrl+q=quit\n\nfont-size = 12\n# New tab\nkeybind = ctrl+t=new_tab\n# Close\n# the tab\nkeybind = ctrl+w=close_surface"
+	if got := string(doc.Bytes()); got != expected {
+		t.Errorf("Expected:\n%q\nGot:\n%q", expected, got)
+	}
+
+	// Changed values are rewritten in place
+	doc.Apply(map[string][]string{"keybind": {"ctrl+q=quit", "ctrl+n=new_window", "ctrl+w=close_surface"}})
+	expected = strings.Replace(expected, "ctrl+t=new_tab", "ctrl+n=new_window", 1)
+	if got := string(doc.Bytes()); got != expected {
+		t.Errorf("Expected:\n%q\nGot:\n%q", expected, got)
+	}
+}
+
+func TestFormat(t *testing.T) {
+	input := "# My Ghostty config\n\n" +
+		"keybind=ctrl+a=new_tab\n" +
+		"# big text\n  font-size   =14  \n\n" +
+		"theme = Dracula\n" +
+		"# --- Input ---\n" +
+		"keybind = ctrl+b=close_surface\n" +
+		"font-family = \"JetBrains Mono\"\n" +
+		"background = 000000\n" +
+		"background = 282c34\n" +
+		"# the end\n"
+
+	expected := "# My Ghostty config\n\n" +
+		"# --- Appearance ---\n" +
+		"background = 000000\n" +
+		"background = 282c34\n" +
+		"theme = Dracula\n\n" +
+		"# --- Font ---\n" +
+		"font-family = \"JetBrains Mono\"\n\n" +
+		"# big text\n" +
+		"font-size = 14\n\n" +
+		"# --- Input ---\n" +
+		"keybind = ctrl+a=new_tab\n" +
+		"keybind = ctrl+b=close_surface\n\n" +
+		"# the end\n"
+	got := string(Format([]byte(input), nil))
+	if got != expected {
+		t.Errorf("Expected:\n%s\nGot:\n%s", expected, got)
+	}
+	if again := string(Format([]byte(got), nil)); again != got {
+		t.Errorf("Expected formatting to be stable, got:\n%s", again)
+	}
+
+	// Minimizing drops overridden entries and defaults, also when they are
+	// spelled differently; their comments move to the next entry
+	options := []schema.Option{
+		{Key: "background", DefaultValue: "#282C34"},
+		{Key: "font-size", DefaultValue: "14.0"},
+		{Key: "theme"},
+	}
+	minimized := string(Format([]byte(input), options))
+	expected = "# My Ghostty config\n\n" +
+		"# --- Appearance ---\n" +
+		"# big text\n" +
+		"theme = Dracula\n\n" +
+		"# --- Font ---\n" +
+		"font-family = \"JetBrains Mono\"\n\n" +
+		"# --- Input ---\n" +
+		"keybind = ctrl+a=new_tab\n" +
+		"keybind = ctrl+b=close_surface\n\n" +
+		"# the end\n"
+	if minimized != expected {
+		t.Errorf("Expected:\n%s\nGot:\n%s", expected, minimized)
+	}
+
+	// Comments of entries dropped after the last one kept stay at the end
+	minimized = string(Format([]byte("theme = Dracula\n# keep me\nnot understood\nfont-size = 14\n"), options))
+	expected = "# --- Appearance ---\ntheme = Dracula\n\n# keep me\nnot understood\n"
+	if minimized != expected {
+		t.Errorf("Expected:\n%s\nGot:\n%s", expected, minimized)
+	}
+}
@@ -0,0 +1,169 @@
+package config
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/otiai10/ghostconfig/internal/schema"
+)
+
+// chunk is an entry together with the comments directly above it
+type chunk struct {
+	comments []*Line
+	entry    *Line
+}
+
+// Format rewrites a config file into the canonical layout: entries grouped
+// by category in display order under "# --- Category ---" headers, sorted
+// by key within a category, and written as "key = value". Comments move
+// with the entry below them; a comment block at the top of the file that
+// is followed by a blank line stays there, and comments after the last
+// entry stay at the end. Repeated keys keep their order, so the value
+// Ghostty uses does not change. A byte order mark is dropped.
+//
+// With options, the file is also minimized: entries of keys that take a
+// single value are dropped if a later entry overrides them or their value
+// means the same as the default (see schema.SameValue). Comments and lines
+// that are not understood above a dropped entry are kept with the next
+// entry, or at the end of the file.
+func Format(data []byte, options []schema.Option) []byte {
+	doc := ParseDocument(data)
+	lines := doc.Lines
+
+	// File header
+	var header []*Line
+	if n := commentBlock(lines); n > 0 && n < len(lines) && lines[n].Kind == LineBlank {
+		header, lines = lines[:n], lines[n:]
+	}
+
+	var chunks []chunk
+	var pending []*Line
+	for _, l := range lines {
+		switch {
+		case l.Kind == LineBlank, isCategoryHeader(l):
+			// Spacing and headers are regenerated
+		case l.Kind == LineEntry && l.Key != "":
+			chunks = append(chunks, chunk{comments: pending, entry: l})
+			pending = nil
+		default:
+			// Comments and lines that are not understood stay with the next entry
+			pending = append(pending, l)
+		}
+	}
+	trailer := pending
+
+	if options != nil {
+		var orphans []*Line
+		chunks, orphans = minimize(chunks, options)
+		trailer = append(orphans, trailer...)
+	}
+	sort.SliceStable(chunks, func(i, j int) bool {
+		a, b := chunks[i].entry.Key, chunks[j].entry.Key
+		ca, cb := schema.CategoryIndex(schema.ExtractSection(a)), schema.CategoryIndex(schema.ExtractSection(b))
+		if ca != cb {
+			return ca < cb
+		}
+		return a < b
+	})
+
+	eol := doc.eol
+	var b strings.Builder
+	writeLine := func(s string) {
+		b.WriteString(strings.TrimRight(s, " \t"))
+		b.WriteString(eol)
+	}
+	for _, l := range header {
+		writeLine(l.Raw)
+	}
+
+	category := ""
+	for i, c := range chunks {
+		if cat := schema.ExtractSection(c.entry.Key); cat != category || i == 0 {
+			category = cat
+			if b.Len() > 0 {
+				writeLine("")
+			}
+			writeLine(categoryHeader(category))
+		} else if len(c.comments) > 0 {
+			// Set commented entries apart from the ones above
+			writeLine("")
+		}
+		for _, l := range c.comments {
+			writeLine(strings.TrimLeft(l.Raw, " \t"))
+		}
+		writeLine(canonicalEntry(c.entry))
+	}
+
+	if len(trailer) > 0 {
+		if b.Len() > 0 {
+			writeLine("")
+		}
+		for _, l := range trailer {
+			writeLine(strings.TrimLeft(l.Raw, " \t"))
+		}
+	}
+	return []byte(b.String())
+}
+
+// commentBlock returns the number of comment lines at the start of lines
+func commentBlock(lines []*Line) int {
+	n := 0
+	for n < len(lines) && lines[n].Kind == LineComment && !isCategoryHeader(lines[n]) {
+		n++
+	}
+	return n
+}
+
+// isCategoryHeader reports whether l is a header written by Apply or Format
+func isCategoryHeader(l *Line) bool {
+	if l.Kind != LineComment {
+		return false
+	}
+	raw := strings.TrimSpace(l.Raw)
+	for _, category := range schema.Categories() {
+		if raw == categoryHeader(category) {
+			return true
+		}
+	}
+	return false
+}
+
+// canonicalEntry returns an entry as "key = value", keeping quotes
+func canonicalEntry(l *Line) string {
+	value := l.Value
+	if l.quoted || needsQuotes(value) {
+		value = `"` + value + `"`
+	}
+	return strings.TrimRight(l.Key+" = "+value, " ")
+}
+
+// minimize drops entries that make no difference to Ghostty. The
+// comments of dropped entries move to the next kept entry; those left
+// after the last one are returned as orphans.
+func minimize(chunks []chunk, options []schema.Option) (kept []chunk, orphans []*Line) {
+	byKey := make(map[string]schema.Option, len(options))
+	for _, opt := range options {
+		byKey[opt.Key] = opt
+	}
+	last := make(map[string]int)
+	for i, c := range chunks {
+		last[c.entry.Key] = i
+	}
+	for i, c := range chunks {
+		key := c.entry.Key
+		if !IsRepeatable(key) {
+			opt, known := byKey[key]
+			if last[key] != i || (known && schema.SameValue(opt, opt.DefaultValue, c.entry.Value)) {
+				// Overridden below or the default
+				orphans = append(orphans, c.comments...)
+				continue
+			}
+		}
+		if len(orphans) > 0 {
+			c.comments = append(orphans, c.comments...)
+			orphans = nil
+		}
+		kept = append(kept, c)
+	}
+	return kept, orphans
+}
@@ -0,0 +1,92 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// loadFile reads a config file, then every file it includes.
+// As in Ghostty, includes are processed after the including file,
+// so their values override it. visited guards against include cycles.
+func (c *Config) loadFile(path string, visited map[string]bool) error {
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return err
+	}
+	if visited[abs] {
+		return fmt.Errorf("include cycle: %s is already loaded", path)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	info, _ := os.Stat(path)
+	c.setBase(path, data, info)
+	visited[abs] = true
+	c.Files = append(c.Files, path)
+
+	doc := ParseDocument(data)
+	c.Diagnostics = append(c.Diagnostics, diagnose(path, doc)...)
+
+	var includes []Source
+	for i, l := range doc.Lines {
+		if l.Kind != LineEntry || l.Key == "" {
+			continue
+		}
+		c.Values[l.Key] = append(c.Values[l.Key], l.Value)
+		c.Sources[l.Key] = append(c.Sources[l.Key], Source{File: path, Line: i + 1})
+		if l.Key == "config-file" {
+			includes = append(includes, Source{File: path, Line: i + 1})
+		}
+	}
+
+	for _, src := range includes {
+		include := doc.Lines[src.Line-1].Value
+		target, optional := resolveInclude(path, include)
+		if target == "" {
+			continue
+		}
+		if err := c.loadFile(target, visited); err != nil {
+			if optional && os.IsNotExist(err) {
+				continue
+			}
+			c.IncludeErrors = append(c.IncludeErrors, fmt.Errorf("config-file = %s (%s): %w", include, path, err))
+			c.Diagnostics = append(c.Diagnostics, Diagnostic{
+				File:     path,
+				Line:     src.Line,
+				Column:   1,
+				Severity: SeverityError,
+				Rule:     RuleInclude,
+				Message:  fmt.Sprintf("config-file = %s: %v", include, err),
+			})
+		}
+	}
+	return nil
+}
+
+// resolveInclude turns a config-file value into a path.
+// A leading "?" marks the file as optional. Relative paths are resolved
+// against the directory of the including file, and "~/" against $HOME.
+func resolveInclude(from, value string) (path string, optional bool) {
+	value = strings.Trim(value, `"`)
+	if strings.HasPrefix(value, "?") {
+		optional = true
+		value = strings.Trim(value[1:], `"`)
+	}
+	if value == "" {
+		return "", optional
+	}
+
+	if strings.HasPrefix(value, "~/") {
+		if home, err := os.UserHomeDir(); err == nil {
+			value = filepath.Join(home, value[2:])
+		}
+	}
+	if !filepath.IsAbs(value) {
+		value = filepath.Join(filepath.Dir(from), value)
+	}
+	return value, optional
+}
@@ -0,0 +1,103 @@
+package config
+
+import "slices"
+
+// keyState is the values and sources of a key at one point in time
+type keyState struct {
+	present bool
+	values  []string
+	sources []Source
+}
+
+func (s keyState) equal(o keyState) bool {
+	return s.present == o.present && slices.Equal(s.values, o.values)
+}
+
+// edit is one journaled change of a key
+type edit struct {
+	key    string
+	before keyState
+	after  keyState
+}
+
+// state captures the current state of key
+func (c *Config) state(key string) keyState {
+	values, present := c.Values[key]
+	return keyState{
+		present: present,
+		values:  slices.Clone(values),
+		sources: slices.Clone(c.sourcesOf(key)),
+	}
+}
+
+// restore puts key back into a captured state.
+// A key that did not exist is kept with no values, so that Save
+// removes any lines written for it in the meantime.
+func (c *Config) restore(key string, s keyState) {
+	values := slices.Clone(s.values)
+	if values == nil {
+		values = []string{}
+	}
+	c.Values[key] = values
+	c.setSources(key, slices.Clone(s.sources))
+}
+
+// record journals a change of key from before to its current state.
+// Mutating methods call it deferred with the state they started from;
+// calls that did not change anything are not recorded.
+func (c *Config) record(key string, before keyState) {
+	after := c.state(key)
+	if before.equal(after) {
+		return
+	}
+	c.undo = append(c.undo, edit{key: key, before: before, after: after})
+	c.redo = nil
+}
+
+// Undo reverts the most recent change and returns the key it affected.
+// The journal lives for the session and survives Save, so saved changes
+// can still be undone; call Save afterwards to write the result.
+func (c *Config) Undo() (string, bool) {
+	if len(c.undo) == 0 {
+		return "", false
+	}
+	e := c.undo[len(c.undo)-1]
+	c.undo = c.undo[:len(c.undo)-1]
+	c.restore(e.key, e.before)
+	c.redo = append(c.redo, e)
+	return e.key, true
+}
+
+// Redo reapplies the most recently undone change and returns its key
+func (c *Config) Redo() (string, bool) {
+	if len(c.redo) == 0 {
+		return "", false
+	}
+	e := c.redo[len(c.redo)-1]
+	c.redo = c.redo[:len(c.redo)-1]
+	c.restore(e.key, e.after)
+	c.undo = append(c.undo, e)
+	return e.key, true
+}
+
+// Revert drops the most recent change without making it redoable,
+// for edits that turn out to be invalid before they are saved
+func (c *Config) Revert() (string, bool) {
+	if len(c.undo) == 0 {
+		return "", false
+	}
+	e := c.undo[len(c.undo)-1]
+	c.undo = c.undo[:len(c.undo)-1]
+	c.restore(e.key, e.before)
+	return e.key, true
+}
+
+// CanUndo reports whether there is a change to undo
+func (c *Config) CanUndo() bool {
+	return len(c.undo) > 0
+}
+
+// CanRedo reports whether there is an undone change to redo
+func (c *Config) CanRedo() bool {
+	return len(c.redo) > 0
+}
@@ -0,0 +1,206 @@
+package config
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"os"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+)
+
+// fileState is what a config file looked like when it was last read or written
+type fileState struct {
+	modTime time.Time
+	size    int64
+	hash    [sha256.Size]byte
+	data    []byte
+}
+
+func newFileState(data []byte, info os.FileInfo) fileState {
+	s := fileState{hash: sha256.Sum256(data), data: data, size: int64(len(data))}
+	if info != nil {
+		s.modTime = info.ModTime()
+	}
+	return s
+}
+
+// Conflict is a key that was changed both in memory and on disk
+// since the file was loaded, to different values
+type Conflict struct {
+	File   string   `json:"file"`
+	Key    string   `json:"key"`
+	Base   []string `json:"base"`
+	Mine   []string `json:"mine"`
+	Theirs []string `json:"theirs"`
+}
+
+// ConflictError is returned by Save when changes made to a file by another
+// program conflict with unsaved edits. Files with conflicts are not written
+// until every conflict is settled with Resolve.
+type ConflictError struct {
+	Conflicts []Conflict
+}
+
+func (e *ConflictError) Error() string {
+	keys := make([]string, len(e.Conflicts))
+	for i, c := range e.Conflicts {
+		keys[i] = c.Key
+	}
+	return fmt.Sprintf("config changed on disk; conflicting keys: %s", strings.Join(keys, ", "))
+}
+
+// Conflicts returns the conflicts waiting to be resolved
+func (c *Config) Conflicts() []Conflict {
+	return c.conflicts
+}
+
+// Resolve settles a conflict by keeping the in-memory values (keepMine)
+// or taking the values found on disk. Resolving is journaled like any edit.
+func (c *Config) Resolve(file, key string, keepMine bool) error {
+	for i, cf := range c.conflicts {
+		if cf.File != file || cf.Key != key {
+			continue
+		}
+		if !keepMine {
+			defer c.record(key, c.state(key))
+			c.setIn(file, key, cf.Theirs)
+		}
+		c.conflicts = append(c.conflicts[:i], c.conflicts[i+1:]...)
+		return nil
+	}
+	return fmt.Errorf("no conflict for %s in %s", key, file)
+}
+
+// ExternalChanges returns the loaded files that were modified on disk
+// by another program since they were loaded or saved.
+// The modification time is checked first, so unchanged files are not read.
+func (c *Config) ExternalChanges() ([]string, error) {
+	var changed []string
+	for _, file := range c.Files {
+		base, known := c.base[file]
+		info, err := os.Stat(file)
+		if os.IsNotExist(err) {
+			if known {
+				changed = append(changed, file)
+			}
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		if known && info.ModTime().Equal(base.modTime) && info.Size() == base.size {
+			continue
+		}
+		data, err := os.ReadFile(file)
+		if err != nil {
+			return nil, err
+		}
+		if !known || sha256.Sum256(data) != base.hash {
+			changed = append(changed, file)
+		}
+	}
+	return changed, nil
+}
+
+// changedOnDisk reports whether data differs from what was last seen of file
+func (c *Config) changedOnDisk(file string, data []byte) bool {
+	base, known := c.base[file]
+	if !known {
+		return len(data) > 0
+	}
+	return sha256.Sum256(data) != base.hash
+}
+
+// merge folds changes made on disk to file into memory with a three-way
+// merge of the last seen contents (base), the in-memory values (mine) and
+// the contents on disk (theirs). Keys changed on only one side take that
+// side's values; keys changed on both sides to different values become
+// conflicts. Afterwards the disk contents are the new base.
+func (c *Config) merge(file string, data []byte, info os.FileInfo) {
+	var base map[string][]string
+	if state, ok := c.base[file]; ok {
+		base = ParseDocument(state.data).Values()
+	}
+	mine := c.valuesIn(file)
+	theirs := ParseDocument(data).Values()
+
+	keys := make(map[string]bool)
+	for _, m := range []map[string][]string{base, mine, theirs} {
+		for key := range m {
+			keys[key] = true
+		}
+	}
+	sorted := make([]string, 0, len(keys))
+	for key := range keys {
+		sorted = append(sorted, key)
+	}
+	sort.Strings(sorted)
+
+	for _, key := range sorted {
+		b, m, t := base[key], mine[key], theirs[key]
+		switch {
+		case slices.Equal(m, t), slices.Equal(t, b):
+			// Nothing to take from disk
+		case slices.Equal(m, b):
+			c.setIn(file, key, t)
+		default:
+			c.conflicts = append(c.conflicts, Conflict{File: file, Key: key, Base: b, Mine: m, Theirs: t})
+		}
+	}
+
+	c.setBase(file, data, info)
+}
+
+func (c *Config) setBase(file string, data []byte, info os.FileInfo) {
+	if c.base == nil {
+		c.base = make(map[string]fileState)
+	}
+	c.base[file] = newFileState(data, info)
+}
+
+// hasConflicts reports whether file has unresolved conflicts
+func (c *Config) hasConflicts(file string) bool {
+	for _, cf := range c.conflicts {
+		if cf.File == file {
+			return true
+		}
+	}
+	return false
+}
+
+// setIn replaces the values of key that belong to file, keeping the
+// values from other files around them
+func (c *Config) setIn(file, key string, values []string) {
+	var merged []string
+	var srcs []Source
+	inserted := false
+	insert := func() {
+		for _, v := range values {
+			merged = append(merged, v)
+			srcs = append(srcs, Source{File: file})
+		}
+		inserted = true
+	}
+
+	for i, src := range c.sourcesOf(key) {
+		if src.File == file {
+			if !inserted {
+				insert()
+			}
+			continue
+		}
+		merged = append(merged, c.Values[key][i])
+		srcs = append(srcs, src)
+	}
+	if !inserted {
+		insert()
+	}
+
+	if merged == nil {
+		merged = []string{}
+	}
+	c.Values[key] = merged
+	c.setSources(key, srcs)
+}
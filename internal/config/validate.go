@@ -0,0 +1,132 @@
+package config
+
+import (
+	"os"
+
+	"github.com/otiai10/ghostconfig/internal/schema"
+)
+
+// ValidationError is a problem Ghostty reported for a value about to be saved
+type ValidationError struct {
+	File    string `json:"file"`
+	Line    int    `json:"line"`
+	Key     string `json:"key"`
+	Message string `json:"message"`
+}
+
+func (e ValidationError) Error() string {
+	pos := Source{File: e.File, Line: e.Line}.String()
+	if e.Key == "" {
+		return pos + ": " + e.Message
+	}
+	return pos + ": " + e.Key + ": " + e.Message
+}
+
+// render returns the contents Save writes to file, given its current contents
+func (c *Config) render(file string, data []byte) ([]byte, error) {
+	doc := ParseDocument(data)
+	if err := doc.Apply(c.valuesIn(file)); err != nil {
+		return nil, err
+	}
+	return doc.Bytes(), nil
+}
+
+// Validate has Ghostty check the config files as Save would write them
+// and returns the problems found with key, or with any key if key is
+// empty. Each file is checked on its own, so problems with config-file
+// includes are left out. An error means Ghostty could not be run.
+func (c *Config) Validate(key string) ([]ValidationError, error) {
+	files := c.Files
+	if len(files) == 0 {
+		files = []string{c.Path}
+	}
+
+	var problems []ValidationError
+	for _, file := range files {
+		data, err := os.ReadFile(file)
+		if err != nil && !os.IsNotExist(err) {
+			return nil, err
+		}
+		out, err := c.render(file, data)
+		if err != nil {
+			return nil, err
+		}
+		results, err := ValidateFile(file, out)
+		if err != nil {
+			return nil, err
+		}
+		for _, r := range results {
+			if key == "" || r.Key == key {
+				problems = append(problems, r)
+			}
+		}
+	}
+	return problems, nil
+}
+
+// ValidateFile has Ghostty check data, the contents of file, on its own
+// and returns the problems it reports, leaving out those with config-file
+// includes. An error means Ghostty could not be run.
+func ValidateFile(file string, data []byte) ([]ValidationError, error) {
+	results, err := schema.Validate(data)
+	if err != nil {
+		return nil, err
+	}
+
+	var problems []ValidationError
+	lines := ParseDocument(data).Lines
+	for _, r := range results {
+		// Ghostty does not always name the key; the line does
+		if r.Key == "" && r.Line > 0 && r.Line <= len(lines) {
+			r.Key = lines[r.Line-1].Key
+		}
+		if r.Key == "config-file" {
+			continue
+		}
+		problems = append(problems, ValidationError{File: file, Line: r.Line, Key: r.Key, Message: r.Message})
+	}
+	return problems, nil
+}
+
+// UndoDepth returns the number of changes that can be undone. Taken
+// before an edit, it tells ValidateEdit which changes the edit made.
+func (c *Config) UndoDepth() int {
+	return len(c.undo)
+}
+
+// ValidateEdit has Ghostty check the changes of key made since the undo
+// depth was depth and returns the problems they introduce. Problems key
+// already had before are left out, and so is an edit that changed nothing.
+// An error means Ghostty could not be run.
+func (c *Config) ValidateEdit(key string, depth int) ([]ValidationError, error) {
+	if depth < 0 || depth >= len(c.undo) {
+		return nil, nil
+	}
+	after, err := c.Validate(key)
+	if err != nil || len(after) == 0 {
+		return nil, err
+	}
+
+	current := c.state(key)
+	c.restore(key, c.undo[depth].before)
+	before, err := c.Validate(key)
+	c.restore(key, current)
+	if err != nil {
+		return nil, err
+	}
+
+	// Lines move with the edit, so problems are told apart by their message
+	known := make(map[string]int)
+	for _, p := range before {
+		known[p.File+"\x00"+p.Message]++
+	}
+	var problems []ValidationError
+	for _, p := range after {
+		if id := p.File + "\x00" + p.Message; known[id] > 0 {
+			known[id]--
+			continue
+		}
+		problems = append(problems, p)
+	}
+	return problems, nil
+}
@@ -2,20 +2,75 @@ package gui
 
 import (
 	"encoding/json"
+	"errors"
 	"net/http"
+	"os"
+	"strings"
 
+	"github.com/otiai10/ghostconfig/internal/config"
 	"github.com/otiai10/ghostconfig/internal/i18n"
 	"github.com/otiai10/ghostconfig/internal/schema"
 )
 
 // OptionResponse represents an option in the API response
 type OptionResponse struct {
-	Key          string `json:"key"`
-	DefaultValue string `json:"defaultValue"`
-	Description  string `json:"description"`
-	Section      string `json:"section"`
-	Type         string `json:"type"`
-	CurrentValue string `json:"currentValue"`
+	Key           string          `json:"key"`
+	DefaultValue  string          `json:"defaultValue"`
+	Description   string          `json:"description"`
+	Section       string          `json:"section"`
+	Type          string          `json:"type"` // see schema.OptionType
+	Choices       []string        `json:"choices,omitempty"`
+	CurrentValue  string          `json:"currentValue"`
+	CurrentValues []string        `json:"currentValues"`
+	Sources       []config.Source `json:"sources"`
+	Repeatable    bool            `json:"repeatable"`
+	Platforms     []string        `json:"platforms,omitempty"`
+	Supported     bool            `json:"supported"` // applies on this platform
+	Since         string          `json:"since,omitempty"`
+	Deprecated    string          `json:"deprecated,omitempty"`
+	// Keybinds holds the parts of each value of keybind
+	Keybinds []KeybindResponse `json:"keybinds,omitempty"`
+}
+
+// KeybindResponse is a keybind value split into its parts.
+// A value that cannot be parsed has only Value and Error.
+type KeybindResponse struct {
+	Value     string   `json:"value"`
+	Prefixes  []string `json:"prefixes"`
+	Trigger   string   `json:"trigger"`
+	Canonical string   `json:"canonical"` // see schema.Keybind.CanonicalTrigger
+	Action    string   `json:"action"`
+	Param     string   `json:"param"`
+	Error     string   `json:"error,omitempty"`
+}
+
+// keybindResponse parses a value of the keybind option
+func keybindResponse(value string) KeybindResponse {
+	kb, err := schema.ParseKeybind(value)
+	if err != nil {
+		return KeybindResponse{Value: value, Prefixes: []string{}, Error: err.Error()}
+	}
+	prefixes := kb.Prefixes
+	if prefixes == nil {
+		prefixes = []string{}
+	}
+	return KeybindResponse{
+		Value:     value,
+		Prefixes:  prefixes,
+		Trigger:   kb.Trigger(),
+		Canonical: kb.CanonicalTrigger(),
+		Action:    kb.Action,
+		Param:     kb.Param,
+	}
+}
+
+// keybinds parses the values of the keybind option
+func keybinds(values []string) []KeybindResponse {
+	resp := make([]KeybindResponse, len(values))
+	for i, v := range values {
+		resp[i] = keybindResponse(v)
+	}
+	return resp
 }
 
 // SectionResponse represents a section with its options
@@ -26,12 +81,16 @@ type SectionResponse struct {
 
 // GET /api/options - Get all options grouped by section
 func (s *Server) handleGetOptions(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
 	if r.Method != http.MethodGet {
 		http.Error(w, i18n.T("gui.method_not_allowed"), http.StatusMethodNotAllowed)
 		return
 	}
 
 	sections := schema.GroupBySection(s.options)
+	platform := schema.CurrentPlatform()
 	var response []SectionResponse
 
 	for _, section := range sections {
@@ -41,26 +100,28 @@ func (s *Server) handleGetOptions(w http.ResponseWriter, r *http.Request) {
 		}
 
 		for _, opt := range section.Options {
-			optType := schema.GetOptionType(opt.Key)
-			typeStr := "text"
-			switch optType {
-			case schema.TypeColor:
-				typeStr = "color"
-			case schema.TypeFont:
-				typeStr = "font"
-			case schema.TypeBool:
-				typeStr = "bool"
-			case schema.TypeNumber:
-				typeStr = "number"
+			meta := opt.Metadata()
+			optType := opt.Type()
+			var binds []KeybindResponse
+			if optType == schema.TypeKeybind {
+				binds = keybinds(s.config.GetAll(opt.Key))
 			}
-
 			sectionData.Options = append(sectionData.Options, OptionResponse{
-				Key:          opt.Key,
-				DefaultValue: opt.DefaultValue,
-				Description:  opt.Description,
-				Section:      section.Name,
-				Type:         typeStr,
-				CurrentValue: s.config.Get(opt.Key),
+				Key:           opt.Key,
+				DefaultValue:  opt.DefaultValue,
+				Description:   opt.Description,
+				Section:       section.Name,
+				Type:          optType.String(),
+				Choices:       meta.Choices,
+				CurrentValue:  s.config.Get(opt.Key),
+				CurrentValues: s.config.GetAll(opt.Key),
+				Sources:       s.config.Sources[opt.Key],
+				Repeatable:    config.IsRepeatable(opt.Key),
+				Platforms:     meta.Platforms,
+				Supported:     meta.Supports(platform),
+				Since:         meta.Since,
+				Deprecated:    meta.Deprecated,
+				Keybinds:      binds,
 			})
 		}
 		response = append(response, sectionData)
@@ -72,33 +133,75 @@ func (s *Server) handleGetOptions(w http.ResponseWriter, r *http.Request) {
 
 // ConfigResponse represents the config API response
 type ConfigResponse struct {
-	Path   string            `json:"path"`
-	Values map[string]string `json:"values"`
+	Path    string                     `json:"path"`
+	Files   []string                   `json:"files"`
+	Values  map[string][]string        `json:"values"`
+	Sources map[string][]config.Source `json:"sources"`
+	CanUndo bool                       `json:"canUndo"`
+	CanRedo bool                       `json:"canRedo"`
+	// Problems lists malformed lines and unknown keys in the config files
+	Problems []config.Diagnostic `json:"problems"`
+	Schema   SchemaResponse      `json:"schema"`
+}
+
+// SchemaResponse tells which Ghostty version the options describe
+type SchemaResponse struct {
+	Version string `json:"version"`
+	// Origin is "ghostty", or "cache" or "embedded" if ghostty is not found
+	Origin string `json:"origin"`
 }
 
 // GET/PUT /api/config - Get or update config values
 func (s *Server) handleConfig(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
 	switch r.Method {
 	case http.MethodGet:
 		w.Header().Set("Content-Type", "application/json")
 		json.NewEncoder(w).Encode(ConfigResponse{
-			Path:   s.config.Path,
-			Values: s.config.Values,
+			Path:     s.config.Path,
+			Files:    s.config.Files,
+			Values:   s.config.Values,
+			Sources:  s.config.Sources,
+			CanUndo:  s.config.CanUndo(),
+			CanRedo:  s.config.CanRedo(),
+			Problems: s.problems(),
+			Schema: SchemaResponse{
+				Version: s.schema.Version,
+				Origin:  string(s.schema.Origin),
+			},
 		})
 
 	case http.MethodPut:
+		// "values" replaces the whole list of a repeatable key.
+		// "force" saves values Ghostty rejects.
 		var req struct {
-			Key   string `json:"key"`
-			Value string `json:"value"`
+			Key    string   `json:"key"`
+			Value  string   `json:"value"`
+			Values []string `json:"values"`
+			Force  bool     `json:"force"`
 		}
 		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
 			http.Error(w, err.Error(), http.StatusBadRequest)
 			return
 		}
 
-		s.config.Set(req.Key, req.Value)
-		if err := s.config.Save(); err != nil {
-			http.Error(w, err.Error(), http.StatusInternalServerError)
+		var err error
+		depth := s.config.UndoDepth()
+		if req.Values != nil {
+			err = s.config.SetAll(req.Key, req.Values)
+		} else {
+			err = s.config.Set(req.Key, req.Value)
+		}
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		if !req.Force && !s.valid(w, req.Key, depth) {
+			return
+		}
+		if !s.save(w) {
 			return
 		}
 
@@ -110,6 +213,453 @@ func (s *Server) handleConfig(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
+// problems returns the diagnostics of the config files, never nil
+func (s *Server) problems() []config.Diagnostic {
+	diags := s.config.Diagnose(s.options, s.keybinds)
+	if diags == nil {
+		diags = []config.Diagnostic{}
+	}
+	return diags
+}
+
+// DELETE /api/config/{key} - Remove a key so Ghostty uses its default
+func (s *Server) handleConfigKey(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if r.Method != http.MethodDelete {
+		http.Error(w, i18n.T("gui.method_not_allowed"), http.StatusMethodNotAllowed)
+		return
+	}
+
+	key := strings.TrimPrefix(r.URL.Path, "/api/config/")
+	if key == "" || strings.Contains(key, "/") {
+		http.NotFound(w, r)
+		return
+	}
+
+	s.config.Unset(key)
+	if !s.save(w) {
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]string{"status": "ok"})
+}
+
+// POST /api/config/values - Add, remove or reorder values of a repeatable key
+func (s *Server) handleConfigValues(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if r.Method != http.MethodPost {
+		http.Error(w, i18n.T("gui.method_not_allowed"), http.StatusMethodNotAllowed)
+		return
+	}
+
+	var req struct {
+		Key    string `json:"key"`
+		Action string `json:"action"` // add, remove or move
+		Value  string `json:"value"`
+		Index  int    `json:"index"`
+		To     int    `json:"to"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	var err error
+	depth := s.config.UndoDepth()
+	switch req.Action {
+	case "add":
+		err = s.config.Add(req.Key, req.Value)
+	case "remove":
+		err = s.config.RemoveAt(req.Key, req.Index)
+	case "move":
+		err = s.config.Move(req.Key, req.Index, req.To)
+	default:
+		http.Error(w, "unknown action: "+req.Action, http.StatusBadRequest)
+		return
+	}
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if req.Action == "add" && !s.valid(w, req.Key, depth) {
+		return
+	}
+	if !s.save(w) {
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string][]string{"values": s.config.GetAll(req.Key)})
+}
+
+// ExternalResponse lists the config files other programs changed
+type ExternalResponse struct {
+	Files []string `json:"files"`
+}
+
+// POST /api/config/external - Merge changes other programs made to the
+// config files. The page polls it; conflicts are answered like a save.
+func (s *Server) handleConfigExternal(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if r.Method != http.MethodPost {
+		http.Error(w, i18n.T("gui.method_not_allowed"), http.StatusMethodNotAllowed)
+		return
+	}
+
+	files, err := s.config.ExternalChanges()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	if len(files) > 0 && !s.save(w) {
+		return
+	}
+	if files == nil {
+		files = []string{}
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(ExternalResponse{Files: files})
+}
+
+// JournalResponse reports the result of an undo or redo
+type JournalResponse struct {
+	Key     string `json:"key"`
+	CanUndo bool   `json:"canUndo"`
+	CanRedo bool   `json:"canRedo"`
+}
+
+// POST /api/undo - Revert the last edit
+func (s *Server) handleUndo(w http.ResponseWriter, r *http.Request) {
+	s.handleJournal(w, r, s.config.Undo)
+}
+
+// POST /api/redo - Reapply the last undone edit
+func (s *Server) handleRedo(w http.ResponseWriter, r *http.Request) {
+	s.handleJournal(w, r, s.config.Redo)
+}
+
+func (s *Server) handleJournal(w http.ResponseWriter, r *http.Request, step func() (string, bool)) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if r.Method != http.MethodPost {
+		http.Error(w, i18n.T("gui.method_not_allowed"), http.StatusMethodNotAllowed)
+		return
+	}
+
+	key, ok := step()
+	if !ok {
+		http.Error(w, http.StatusText(http.StatusConflict), http.StatusConflict)
+		return
+	}
+	if !s.save(w) {
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(JournalResponse{
+		Key:     key,
+		CanUndo: s.config.CanUndo(),
+		CanRedo: s.config.CanRedo(),
+	})
+}
+
+// ConflictsResponse lists edits that clash with changes made on disk
+type ConflictsResponse struct {
+	Conflicts []config.Conflict `json:"conflicts"`
+}
+
+// save writes the config. On failure it writes the error response and
+// returns false: 409 with the conflicts when the files were changed on
+// disk in a conflicting way, 500 otherwise.
+func (s *Server) save(w http.ResponseWriter) bool {
+	err := s.config.Save()
+	var conflictErr *config.ConflictError
+	if errors.As(err, &conflictErr) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusConflict)
+		json.NewEncoder(w).Encode(ConflictsResponse{Conflicts: conflictErr.Conflicts})
+		return false
+	}
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return false
+	}
+	return true
+}
+
+// ValidationResponse lists the problems Ghostty found with an edit
+type ValidationResponse struct {
+	Errors []config.ValidationError `json:"errors"`
+}
+
+// valid has Ghostty check the edit of key made since the undo depth was
+// depth (see config.ValidateEdit). A rejected edit is reverted and
+// answered with 422 and the problems. If Ghostty cannot be run, the edit
+// is let through unchecked.
+func (s *Server) valid(w http.ResponseWriter, key string, depth int) bool {
+	problems, err := s.config.ValidateEdit(key, depth)
+	if err != nil || len(problems) == 0 {
+		return true
+	}
+	s.config.Revert()
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusUnprocessableEntity)
+	json.NewEncoder(w).Encode(ValidationResponse{Errors: problems})
+	return false
+}
+
+// GET /api/conflicts - List unresolved conflicts with changes made on disk
+func (s *Server) handleConflicts(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if r.Method != http.MethodGet {
+		http.Error(w, i18n.T("gui.method_not_allowed"), http.StatusMethodNotAllowed)
+		return
+	}
+
+	conflicts := s.config.Conflicts()
+	if conflicts == nil {
+		conflicts = []config.Conflict{}
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(ConflictsResponse{Conflicts: conflicts})
+}
+
+// POST /api/conflicts/resolve - Keep mine or take theirs for one conflict.
+// The config is saved once the last conflict is resolved.
+func (s *Server) handleConflictsResolve(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if r.Method != http.MethodPost {
+		http.Error(w, i18n.T("gui.method_not_allowed"), http.StatusMethodNotAllowed)
+		return
+	}
+
+	var req struct {
+		File string `json:"file"`
+		Key  string `json:"key"`
+		Keep string `json:"keep"` // mine or theirs
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if req.Keep != "mine" && req.Keep != "theirs" {
+		http.Error(w, "unknown keep: "+req.Keep, http.StatusBadRequest)
+		return
+	}
+
+	if err := s.config.Resolve(req.File, req.Key, req.Keep == "mine"); err != nil {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+	if len(s.config.Conflicts()) == 0 && !s.save(w) {
+		return
+	}
+
+	conflicts := s.config.Conflicts()
+	if conflicts == nil {
+		conflicts = []config.Conflict{}
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(ConflictsResponse{Conflicts: conflicts})
+}
+
+// ChangesResponse lists how the config differs from the defaults or another file
+type ChangesResponse struct {
+	From    string          `json:"from"`
+	To      string          `json:"to"`
+	Changes []config.Change `json:"changes"`
+}
+
+// GET /api/changes - Compare the config to the defaults, or to another file with ?file=
+func (s *Server) handleChanges(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if r.Method != http.MethodGet {
+		http.Error(w, i18n.T("gui.method_not_allowed"), http.StatusMethodNotAllowed)
+		return
+	}
+
+	resp := ChangesResponse{From: "", To: s.config.Path}
+	from, to := config.Defaults(s.options), s.config.Values
+	if file := r.URL.Query().Get("file"); file != "" {
+		other, err := config.Load(file)
+		if err == nil {
+			_, err = os.Stat(other.Path)
+		}
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusNotFound)
+			return
+		}
+		resp.From, resp.To = s.config.Path, other.Path
+		from, to = s.config.Values, other.Values
+	}
+
+	resp.Changes = config.Compare(from, to, s.options)
+	if resp.Changes == nil {
+		resp.Changes = []config.Change{}
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(resp)
+}
+
+// SchemaChangesResponse lists what changed in the options between two
+// Ghostty versions. From is empty if no other version is known.
+type SchemaChangesResponse struct {
+	From     string                `json:"from"`
+	To       string                `json:"to"`
+	Versions []string              `json:"versions"`
+	Changes  []schema.OptionChange `json:"changes"`
+	// Affected lists the keys set in the config that were removed or
+	// whose default changed
+	Affected []string `json:"affected"`
+}
+
+// GET /api/schema/changes - Compare the schema to the one of the previous
+// Ghostty version seen, or to another version with ?from=
+func (s *Server) handleSchemaChanges(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if r.Method != http.MethodGet {
+		http.Error(w, i18n.T("gui.method_not_allowed"), http.StatusMethodNotAllowed)
+		return
+	}
+
+	resp := SchemaChangesResponse{
+		To:       s.schema.Version,
+		Versions: schema.Versions(),
+		Changes:  []schema.OptionChange{},
+		Affected: []string{},
+	}
+	var from schema.Schema
+	ok := false
+	if version := r.URL.Query().Get("from"); version != "" {
+		var err error
+		if from, err = schema.LoadVersion(version); err != nil {
+			http.Error(w, err.Error(), http.StatusNotFound)
+			return
+		}
+		ok = true
+	} else {
+		from, ok = schema.Previous(s.schema.Version)
+	}
+	if ok {
+		resp.From = from.Version
+		if changes := schema.Diff(from.Options, s.options); changes != nil {
+			resp.Changes = changes
+		}
+		resp.Affected = config.Affected(s.config.Values, resp.Changes)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(resp)
+}
+
+// HistoryResponse lists the snapshots of the config files
+type HistoryResponse struct {
+	Enabled   bool              `json:"enabled"`
+	Snapshots []config.Snapshot `json:"snapshots"`
+}
+
+// GET /api/history - List snapshots, or diff one against the current file with ?id=
+func (s *Server) handleHistory(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if r.Method != http.MethodGet {
+		http.Error(w, i18n.T("gui.method_not_allowed"), http.StatusMethodNotAllowed)
+		return
+	}
+
+	store := s.config.Backups
+	w.Header().Set("Content-Type", "application/json")
+	if store == nil {
+		json.NewEncoder(w).Encode(HistoryResponse{Snapshots: []config.Snapshot{}})
+		return
+	}
+
+	if id := r.URL.Query().Get("id"); id != "" {
+		snap, err := store.Find(id)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusNotFound)
+			return
+		}
+		diff, err := store.Diff(snap)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		json.NewEncoder(w).Encode(struct {
+			Snapshot *config.Snapshot `json:"snapshot"`
+			Diff     string           `json:"diff"`
+		}{snap, diff})
+		return
+	}
+
+	snapshots, err := store.List(s.config.Files...)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	if snapshots == nil {
+		snapshots = []config.Snapshot{}
+	}
+	json.NewEncoder(w).Encode(HistoryResponse{Enabled: true, Snapshots: snapshots})
+}
+
+// POST /api/history/restore - Restore a snapshot and reload the config
+func (s *Server) handleHistoryRestore(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if r.Method != http.MethodPost {
+		http.Error(w, i18n.T("gui.method_not_allowed"), http.StatusMethodNotAllowed)
+		return
+	}
+	if s.config.Backups == nil {
+		http.Error(w, i18n.T("cli.backups_disabled"), http.StatusBadRequest)
+		return
+	}
+
+	var req struct {
+		ID string `json:"id"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	snap, err := s.config.Backups.Find(req.ID)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+	if err := s.config.Backups.Restore(snap); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	if err := s.config.Reload(); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]string{"status": "ok"})
+}
+
 // GET /api/fonts - Get available fonts
 func (s *Server) handleGetFonts(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodGet {
@@ -127,6 +677,61 @@ func (s *Server) handleGetFonts(w http.ResponseWriter, r *http.Request) {
 	json.NewEncoder(w).Encode(fonts)
 }
 
+// GET /api/themes - Get available themes with their colors
+func (s *Server) handleGetThemes(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, i18n.T("gui.method_not_allowed"), http.StatusMethodNotAllowed)
+		return
+	}
+
+	themes, err := schema.ListThemes()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(themes)
+}
+
+// GET /api/actions - Get the keybind actions with their docs
+func (s *Server) handleGetActions(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, i18n.T("gui.method_not_allowed"), http.StatusMethodNotAllowed)
+		return
+	}
+
+	actions, err := schema.ListActions()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(actions)
+}
+
+// GET /api/keybinds - Get the keybinds Ghostty has by default
+func (s *Server) handleGetKeybinds(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, i18n.T("gui.method_not_allowed"), http.StatusMethodNotAllowed)
+		return
+	}
+
+	defaults, err := schema.ListKeybinds()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	resp := make([]KeybindResponse, len(defaults))
+	for i, kb := range defaults {
+		resp[i] = keybindResponse(kb.String())
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(resp)
+}
+
 // ColorOption represents a preset color
 type ColorOption struct {
 	Name  string `json:"name"`
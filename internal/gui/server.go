@@ -8,6 +8,7 @@ import (
 	"net/http"
 	"os"
 	"os/signal"
+	"sync"
 	"syscall"
 	"time"
 
@@ -22,6 +23,11 @@ var staticFiles embed.FS
 // Server represents the GUI HTTP server
 type Server struct {
 	options  []schema.Option
+	schema   schema.Schema
+	keybinds []schema.Keybind // Ghostty's defaults, empty if it cannot list them
+	// mu serializes the handlers that read or change config, as requests
+	// are served concurrently
+	mu       sync.Mutex
 	config   *config.Config
 	port     int
 	server   *http.Server
@@ -29,9 +35,12 @@ type Server struct {
 }
 
 // NewServer creates a new GUI server
-func NewServer(options []schema.Option, cfg *config.Config, port int) *Server {
+func NewServer(sch schema.Schema, cfg *config.Config, port int) *Server {
+	keybinds, _ := schema.ListKeybinds()
 	return &Server{
-		options:  options,
+		options:  sch.Options,
+		schema:   sch,
+		keybinds: keybinds,
 		config:   cfg,
 		port:     port,
 		shutdown: make(chan struct{}, 1),
@@ -45,7 +54,21 @@ func (s *Server) Start() error {
 	// API endpoints
 	mux.HandleFunc("/api/options", s.handleGetOptions)
 	mux.HandleFunc("/api/config", s.handleConfig)
+	mux.HandleFunc("/api/config/", s.handleConfigKey)
+	mux.HandleFunc("/api/config/values", s.handleConfigValues)
+	mux.HandleFunc("/api/config/external", s.handleConfigExternal)
+	mux.HandleFunc("/api/undo", s.handleUndo)
+	mux.HandleFunc("/api/redo", s.handleRedo)
+	mux.HandleFunc("/api/conflicts", s.handleConflicts)
+	mux.HandleFunc("/api/conflicts/resolve", s.handleConflictsResolve)
+	mux.HandleFunc("/api/changes", s.handleChanges)
+	mux.HandleFunc("/api/schema/changes", s.handleSchemaChanges)
+	mux.HandleFunc("/api/history", s.handleHistory)
+	mux.HandleFunc("/api/history/restore", s.handleHistoryRestore)
 	mux.HandleFunc("/api/fonts", s.handleGetFonts)
+	mux.HandleFunc("/api/themes", s.handleGetThemes)
+	mux.HandleFunc("/api/actions", s.handleGetActions)
+	mux.HandleFunc("/api/keybinds", s.handleGetKeybinds)
 	mux.HandleFunc("/api/colors", s.handleGetColors)
 	mux.HandleFunc("/api/exit", s.handleExit)
 	mux.HandleFunc("/api/i18n", s.handleGetI18n)
@@ -24,8 +24,18 @@ const state = {
     searchQuery: '',
     colors: [],
     fonts: [],
+    themes: [],
+    themeValue: null,
+    themeSlot: 'light',
+    actions: null,
+    defaultKeybinds: null,
     currentOption: null,
-    configPath: ''
+    configPath: '',
+    schema: null,
+    selectedSnapshot: null,
+    canUndo: false,
+    canRedo: false,
+    problems: []
 };
 
 // Initialize the application
@@ -40,9 +50,11 @@ async function init() {
         renderLangSwitcher();
         applyI18n();
         renderConfigPath();
+        renderUndoState();
         renderSections();
         renderOptions();
         setupEventListeners();
+        setInterval(() => watchExternalChanges().catch(() => {}), watchInterval);
     } catch (error) {
         console.error(t('gui.error.init'), error);
         document.getElementById('options').innerHTML =
@@ -87,6 +99,7 @@ function switchLanguage(lang) {
     // Update UI
     renderLangSwitcher();
     applyI18n();
+    renderConfigPath();
     renderSections();
     renderOptions();
 }
@@ -106,6 +119,25 @@ function applyI18n() {
     if (modalSave) modalSave.textContent = t('gui.save');
     const loading = document.querySelector('.loading');
     if (loading) loading.textContent = t('gui.loading');
+    document.getElementById('undo-btn').textContent = t('gui.undo');
+    document.getElementById('redo-btn').textContent = t('gui.redo');
+    document.getElementById('history-btn').textContent = t('gui.history');
+    document.getElementById('changes-btn').textContent = t('gui.changes');
+    document.getElementById('changes-title').textContent = t('gui.changes');
+    document.getElementById('changes-file').placeholder = t('gui.changes_file');
+    document.getElementById('changes-compare').textContent = t('gui.changes_compare');
+    document.getElementById('changes-close').textContent = t('gui.close');
+    document.getElementById('whatsnew-btn').textContent = t('gui.whatsnew');
+    document.getElementById('whatsnew-title').textContent = t('gui.whatsnew');
+    document.getElementById('whatsnew-from-label').textContent = t('gui.whatsnew_from');
+    document.getElementById('whatsnew-close').textContent = t('gui.close');
+    document.getElementById('history-title').textContent = t('gui.history');
+    document.getElementById('history-close').textContent = t('gui.close');
+    document.getElementById('history-restore').textContent = t('gui.history_restore');
+    document.getElementById('problems-title').textContent = t('gui.problems');
+    renderProblems();
+    document.getElementById('conflicts-title').textContent = t('tui.conflicts');
+    document.getElementById('conflicts-desc').textContent = t('tui.conflicts_desc');
 }
 
 // API calls
@@ -126,6 +158,11 @@ async function loadConfigPath() {
     if (!response.ok) return;
     const data = await response.json();
     state.configPath = data.path || '';
+    state.canUndo = data.canUndo;
+    state.canRedo = data.canRedo;
+    state.problems = data.problems || [];
+    state.schema = data.schema || null;
+    renderProblems();
 }
 
 function renderConfigPath() {
@@ -133,6 +170,14 @@ function renderConfigPath() {
     if (pathEl && state.configPath) {
         pathEl.textContent = state.configPath;
     }
+    const schemaEl = document.getElementById('schema-version');
+    if (schemaEl && state.schema) {
+        // Options from the cache or the built-in snapshot may not match
+        // the Ghostty the config is used with
+        const version = state.schema.version || t('schema.version_unknown');
+        schemaEl.textContent = t('schema.origin.' + state.schema.origin).replace('%s', version);
+        schemaEl.classList.toggle('fallback', state.schema.origin !== 'ghostty');
+    }
 }
 
 async function loadFonts() {
@@ -143,29 +188,233 @@ async function loadFonts() {
     return state.fonts;
 }
 
-async function saveConfig(key, value) {
+async function loadThemes() {
+    if (state.themes.length > 0) return state.themes;
+    const response = await fetch('/api/themes');
+    if (!response.ok) throw new Error(t('gui.error.load_themes'));
+    state.themes = await response.json();
+    return state.themes;
+}
+
+// Actions and default keybinds only help with editing keybinds, so the
+// editor works without them when ghostty cannot list them
+async function loadKeybindDefaults() {
+    if (state.actions !== null) return;
+    const [actions, keybinds] = await Promise.all(['/api/actions', '/api/keybinds'].map(url =>
+        fetch(url).then(response => response.ok ? response.json() : []).catch(() => [])));
+    state.actions = actions;
+    state.defaultKeybinds = keybinds;
+}
+
+async function saveConfig(key, value, force = false) {
     const response = await fetch('/api/config', {
         method: 'PUT',
         headers: { 'Content-Type': 'application/json' },
-        body: JSON.stringify({ key, value })
+        body: JSON.stringify({ key, value, force })
     });
 
+    if (response.status === 422) {
+        if (confirmInvalid(key, await response.json())) {
+            return saveConfig(key, value, true);
+        }
+        throw new Error(t('gui.error.invalid'));
+    }
+
+    if (response.status === 409) {
+        await openConflicts(await response.json());
+        return;
+    }
     if (!response.ok) {
         throw new Error(t('gui.error.save'));
     }
 
-    // Update local state
-    for (const section of state.sections) {
-        for (const opt of section.options) {
-            if (opt.key === key) {
-                opt.currentValue = value;
-                break;
-            }
+    // Reload options so values and their source files stay in sync
+    await Promise.all([loadOptions(), loadConfigPath()]);
+    renderUndoState();
+
+    showStatus(t('msg.saved').replace('%s', key).replace('%s', value));
+    renderOptions();
+}
+
+// Replace all values of a repeatable key
+async function saveConfigValues(key, values, force = false) {
+    const response = await fetch('/api/config', {
+        method: 'PUT',
+        headers: { 'Content-Type': 'application/json' },
+        body: JSON.stringify({ key, values, force })
+    });
+
+    if (response.status === 422) {
+        if (confirmInvalid(key, await response.json())) {
+            return saveConfigValues(key, values, true);
         }
+        throw new Error(t('gui.error.invalid'));
     }
 
-    showStatus(t('msg.saved').replace('%s', key).replace('%s', value));
+    if (response.status === 409) {
+        await openConflicts(await response.json());
+        return;
+    }
+    if (!response.ok) {
+        throw new Error(t('gui.error.save'));
+    }
+
+    await Promise.all([loadOptions(), loadConfigPath()]);
+    renderUndoState();
+
+    showStatus(t('msg.saved').replace('%s', key).replace('%s', values.join(', ')));
+    renderOptions();
+}
+
+// Ask whether to save a value Ghostty rejects, naming the problems
+function confirmInvalid(key, data) {
+    const problems = (data.errors || []).map(e => {
+        const pos = e.line > 0 ? `${e.file}:${e.line}` : e.file;
+        return `${e.key || key}: ${e.message} (${pos})`;
+    });
+    return confirm(t('gui.confirm_invalid') + '\n\n' + problems.join('\n'));
+}
+
+// Remove a key from the config so Ghostty uses its default
+async function resetOption(key) {
+    const response = await fetch(`/api/config/${encodeURIComponent(key)}`, { method: 'DELETE' });
+    if (response.status === 409 && isJson(response)) {
+        await openConflicts(await response.json());
+        return;
+    }
+    if (!response.ok) {
+        showStatus(t('gui.error.reset'), true);
+        return;
+    }
+
+    await Promise.all([loadOptions(), loadConfigPath()]);
     renderOptions();
+    renderUndoState();
+    showStatus(t('msg.reset').replace('%s', key));
+}
+
+// Undo or redo the last edit; action is 'undo' or 'redo'
+async function stepJournal(action) {
+    const response = await fetch(`/api/${action}`, { method: 'POST' });
+    // A plain 409 means there is nothing to step; a JSON one lists conflicts
+    if (response.status === 409 && isJson(response)) {
+        await openConflicts(await response.json());
+        return;
+    }
+    if (!response.ok) {
+        showStatus(t(action === 'undo' ? 'msg.nothing_to_undo' : 'msg.nothing_to_redo'), true);
+        return;
+    }
+    const data = await response.json();
+    state.canUndo = data.canUndo;
+    state.canRedo = data.canRedo;
+
+    await loadOptions();
+    renderOptions();
+    renderUndoState();
+    showStatus(t(action === 'undo' ? 'msg.undone' : 'msg.redone').replace('%s', data.key));
+}
+
+function renderUndoState() {
+    document.getElementById('undo-btn').disabled = !state.canUndo;
+    document.getElementById('redo-btn').disabled = !state.canRedo;
+}
+
+// Problems panel: malformed lines and unknown keys in the config files
+function renderProblems() {
+    const btn = document.getElementById('problems-btn');
+    const panel = document.getElementById('problems');
+    const count = state.problems.length;
+
+    btn.textContent = t('gui.problems_count').replace('%d', count);
+    btn.classList.toggle('hidden', count === 0);
+    if (count === 0) panel.classList.add('hidden');
+
+    document.getElementById('problems-list').innerHTML = state.problems.map(p => {
+        const pos = p.line > 0 ? `${p.file}:${p.line}:${p.column}` : p.file;
+        return `
+            <li class="problem problem-${escapeHtml(p.severity)}">
+                <span class="problem-severity">${escapeHtml(t('gui.severity.' + p.severity))}</span>
+                <span class="problem-message">${escapeHtml(p.message)}</span>
+                <span class="problem-location">${escapeHtml(pos)}</span>
+            </li>
+        `;
+    }).join('');
+}
+
+function isJson(response) {
+    return (response.headers.get('Content-Type') || '').includes('application/json');
+}
+
+// Conflicts between edits here and changes made to the files on disk
+async function openConflicts(data) {
+    if (!data.conflicts || data.conflicts.length === 0) {
+        closeConflicts();
+        await Promise.all([loadOptions(), loadConfigPath()]);
+        renderOptions();
+        renderUndoState();
+        showStatus(t('msg.conflicts_resolved'));
+        return;
+    }
+
+    const list = document.getElementById('conflicts-list');
+    list.innerHTML = data.conflicts.map(cf => `
+        <div class="conflict-item" data-file="${escapeHtml(cf.file)}" data-key="${escapeHtml(cf.key)}">
+            <div class="conflict-header">
+                <span class="option-key">${escapeHtml(cf.key)}</span>
+                <span class="history-file">${escapeHtml(cf.file)}</span>
+            </div>
+            <div class="conflict-values">
+                <div><span class="conflict-label">${escapeHtml(t('tui.conflict_mine'))}</span> <code>${escapeHtml((cf.mine || []).join(', '))}</code></div>
+                <div><span class="conflict-label">${escapeHtml(t('tui.conflict_theirs'))}</span> <code>${escapeHtml((cf.theirs || []).join(', '))}</code></div>
+                <div class="conflict-base"><span class="conflict-label">${escapeHtml(t('tui.conflict_base'))}</span> <code>${escapeHtml((cf.base || []).join(', '))}</code></div>
+            </div>
+            <div class="modal-actions">
+                <button class="btn-secondary" data-keep="theirs">${t('gui.keep_theirs')}</button>
+                <button class="btn-primary" data-keep="mine">${t('gui.keep_mine')}</button>
+            </div>
+        </div>
+    `).join('');
+    document.getElementById('conflicts-modal').classList.remove('hidden');
+    showStatus(t('msg.conflicts').replace('%d', data.conflicts.length), true);
+}
+
+async function resolveConflict(file, key, keep) {
+    const response = await fetch('/api/conflicts/resolve', {
+        method: 'POST',
+        headers: { 'Content-Type': 'application/json' },
+        body: JSON.stringify({ file, key, keep })
+    });
+    if (!response.ok && !(response.status === 409 && isJson(response))) {
+        showStatus(t('gui.error.save_prefix') + await response.text(), true);
+        return;
+    }
+    await openConflicts(await response.json());
+}
+
+// How often the config files are checked for changes made by other programs
+const watchInterval = 3000;
+
+// Merge changes other programs made to the config files and show them
+async function watchExternalChanges() {
+    if (!document.getElementById('conflicts-modal').classList.contains('hidden')) return;
+    const response = await fetch('/api/config/external', { method: 'POST' });
+    if (response.status === 409 && isJson(response)) {
+        await openConflicts(await response.json());
+        return;
+    }
+    if (!response.ok) return;
+    const data = await response.json();
+    if (data.files.length === 0) return;
+
+    await Promise.all([loadOptions(), loadConfigPath()]);
+    renderOptions();
+    renderUndoState();
+    showStatus(t('msg.external_changes').replace('%s', data.files.join(', ')));
+}
+
+function closeConflicts() {
+    document.getElementById('conflicts-modal').classList.add('hidden');
 }
 
 // Rendering functions
@@ -206,25 +455,46 @@ function renderOptions() {
     for (const opt of filtered) {
         const displayValue = opt.currentValue || opt.defaultValue || '(empty)';
         const isModified = opt.currentValue && opt.currentValue !== opt.defaultValue;
-        const badge = isModified
+        const isSet = (opt.currentValues || []).length > 0;
+        let badge = isModified
             ? `<span class="modified-badge">${t('gui.modified')}</span>`
             : (!opt.currentValue ? `<span class="default-badge">${t('gui.default')}</span>` : '');
+        if (isSet) {
+            badge = `<span class="badges">${badge}<button class="reset-btn" data-key="${escapeHtml(opt.key)}" title="${t('gui.reset')}">↺</button></span>`;
+        }
 
         let valueHtml = escapeHtml(displayValue);
-        if (opt.type === 'color' && displayValue && displayValue !== '(empty)') {
+        const values = opt.currentValues || [];
+        if (opt.repeatable && values.length > 1) {
+            valueHtml = `<span class="values-count">${t('gui.values_count').replace('%d', values.length)}</span>` +
+                `<ul class="option-values">${values.map((v, i) => `<li title="${escapeHtml(formatSource((opt.sources || [])[i]))}">${escapeHtml(v)}</li>`).join('')}</ul>`;
+        } else if (opt.type === 'color' && displayValue && displayValue !== '(empty)') {
             const colorVal = displayValue.startsWith('#') ? displayValue : `#${displayValue}`;
             valueHtml = `<span class="color-preview" style="background: ${colorVal}"></span> ${escapeHtml(displayValue)}`;
         }
 
         const description = translateDescription(opt.key, opt.description);
+        const sourceHtml = renderSource(opt);
+        const deprecatedHtml = opt.deprecated
+            ? `<div class="option-deprecated">${escapeHtml(t('gui.deprecated_note').replace('%s', opt.deprecated))}</div>`
+            : '';
+
+        // Options for other platforms stay editable, as configs are often shared
+        const cardClass = opt.supported ? 'option-card' : 'option-card unsupported';
+        const cardTitle = opt.supported ? '' : `title="${escapeHtml(t('gui.unsupported'))}"`;
 
         html += `
-            <div class="option-card" data-key="${opt.key}">
+            <div class="${cardClass}" data-key="${opt.key}" ${cardTitle}>
                 <div class="option-header">
-                    <span class="option-key">${escapeHtml(opt.key)}</span>
+                    <span class="option-title">
+                        <span class="option-key">${escapeHtml(opt.key)}</span>
+                        ${renderMetaBadges(opt)}
+                    </span>
                     ${badge}
                 </div>
                 <div class="option-value">${valueHtml}</div>
+                ${sourceHtml}
+                ${deprecatedHtml}
                 <div class="option-description">${escapeHtml(description)}</div>
             </div>
         `;
@@ -233,6 +503,37 @@ function renderOptions() {
     container.innerHTML = html;
 }
 
+// Badges for the platforms, version and deprecation of an option
+function renderMetaBadges(opt) {
+    let html = '';
+    if (opt.deprecated) {
+        html += `<span class="meta-badge deprecated">${t('gui.deprecated')}</span>`;
+    }
+    if (opt.platforms && opt.platforms.length > 0) {
+        const names = opt.platforms.map(p => t('platform.' + p)).join(', ');
+        html += `<span class="meta-badge">${escapeHtml(t('gui.platform_only').replace('%s', names))}</span>`;
+    }
+    if (opt.since) {
+        html += `<span class="meta-badge">${escapeHtml(t('gui.since').replace('%s', opt.since))}</span>`;
+    }
+    return html;
+}
+
+// Format a config.Source as file:line
+function formatSource(src) {
+    if (!src) return '';
+    return src.line ? `${src.file}:${src.line}` : src.file;
+}
+
+// Show where the effective value comes from when it is not the main config file
+function renderSource(opt) {
+    const sources = opt.sources || [];
+    if (sources.length === 0) return '';
+    const src = sources[sources.length - 1];
+    if (src.file === state.configPath) return '';
+    return `<div class="option-source">${escapeHtml(t('gui.defined_in').replace('%s', formatSource(src)))}</div>`;
+}
+
 function filterOptions() {
     let options = [];
 
@@ -275,6 +576,19 @@ function openEditor(option) {
     description.textContent = translateDescription(option.key, option.description);
 
     const currentValue = option.currentValue || option.defaultValue || '';
+    modal.querySelector('.modal-content').classList.toggle('modal-wide', option.type === 'keybind');
+
+    if (option.type === 'keybind') {
+        renderKeybindEditor(container, option.keybinds || []);
+        modal.classList.remove('hidden');
+        return;
+    }
+
+    if (option.repeatable) {
+        renderValuesEditor(container, option.currentValues || []);
+        modal.classList.remove('hidden');
+        return;
+    }
 
     switch (option.type) {
         case 'color':
@@ -283,8 +597,15 @@ function openEditor(option) {
         case 'font':
             renderFontPicker(container, currentValue);
             break;
+        case 'theme':
+            renderThemePicker(container, currentValue);
+            break;
+        case 'bool':
+        case 'enum':
+            renderChoices(container, option, currentValue);
+            break;
         default:
-            container.innerHTML = `<input type="text" id="edit-value" value="${escapeHtml(currentValue)}" placeholder="${escapeHtml(option.defaultValue || '')}">`;
+            renderTextInput(container, option, currentValue);
     }
 
     modal.classList.remove('hidden');
@@ -296,6 +617,28 @@ function openEditor(option) {
     }
 }
 
+// A select for bool and enum options; saveEditor reads #edit-value
+function renderChoices(container, option, currentValue) {
+    const choices = option.type === 'bool' ? ['true', 'false'] : option.choices;
+    const items = choices.map(choice => {
+        const label = choice === option.defaultValue ? `${choice} (${t('gui.default')})` : choice;
+        const selected = choice === currentValue ? 'selected' : '';
+        return `<option value="${escapeHtml(choice)}" ${selected}>${escapeHtml(label)}</option>`;
+    });
+    container.innerHTML = `<select id="edit-value">${items.join('')}</select>`;
+}
+
+// A text input with a hint for numbers, durations and paths.
+// Numbers stay text inputs as some options take pairs such as "2,4".
+function renderTextInput(container, option, currentValue) {
+    const inputMode = option.type === 'number' || option.type === 'integer' ? 'inputmode="decimal"' : '';
+    let html = `<input type="text" id="edit-value" ${inputMode} value="${escapeHtml(currentValue)}" placeholder="${escapeHtml(option.defaultValue || '')}">`;
+    if (['number', 'integer', 'duration', 'path'].includes(option.type)) {
+        html += `<div class="input-hint">${escapeHtml(t('type.' + option.type))}</div>`;
+    }
+    container.innerHTML = html;
+}
+
 function closeModal() {
     document.getElementById('modal').classList.add('hidden');
     state.currentOption = null;
@@ -351,6 +694,194 @@ function renderColorPicker(container, currentValue) {
     });
 }
 
+// Editor for repeatable keys: one row per value with reorder and remove buttons
+function renderValuesEditor(container, values) {
+    const rows = values.map(v => valueRowHtml(v)).join('');
+    container.innerHTML = `
+        <div class="values-editor" id="values-editor">${rows}</div>
+        <button class="btn-secondary btn-add-value" id="add-value">${t('gui.add_value')}</button>
+    `;
+
+    const editor = document.getElementById('values-editor');
+    document.getElementById('add-value').addEventListener('click', () => {
+        editor.insertAdjacentHTML('beforeend', valueRowHtml(''));
+        const inputs = editor.querySelectorAll('input');
+        inputs[inputs.length - 1].focus();
+    });
+
+    editor.addEventListener('click', handleRowButton);
+}
+
+// Move or remove the row of an up, down or remove button
+function handleRowButton(e) {
+    const btn = e.target.closest('button');
+    if (!btn) return;
+    const row = btn.closest('.value-row');
+    switch (btn.dataset.action) {
+        case 'up':
+            if (row.previousElementSibling) row.parentNode.insertBefore(row, row.previousElementSibling);
+            break;
+        case 'down':
+            if (row.nextElementSibling) row.parentNode.insertBefore(row.nextElementSibling, row);
+            break;
+        case 'remove':
+            row.remove();
+            break;
+    }
+}
+
+function rowButtonsHtml() {
+    return `
+        <button class="btn-icon" data-action="up" title="${t('gui.move_up')}">↑</button>
+        <button class="btn-icon" data-action="down" title="${t('gui.move_down')}">↓</button>
+        <button class="btn-icon" data-action="remove" title="${t('gui.remove')}">×</button>`;
+}
+
+function valueRowHtml(value) {
+    return `
+        <div class="value-row">
+            <input type="text" value="${escapeHtml(value)}">${rowButtonsHtml()}
+        </div>
+    `;
+}
+
+// Keybind table: prefixes, key sequence, action and parameter per binding,
+// written back as one keybind line per row
+const keybindPrefixes = ['global', 'all', 'unconsumed', 'performable'];
+
+function renderKeybindEditor(container, keybinds) {
+    const header = `
+        <div class="keybind-header">
+            <span>${t('gui.keybind_prefixes')}</span>
+            <span>${t('gui.keybind_trigger')}</span>
+            <span>${t('gui.keybind_action')}</span>
+            <span>${t('gui.keybind_param')}</span>
+        </div>`;
+    container.innerHTML = `
+        ${header}
+        <div class="values-editor keybind-editor" id="keybind-editor">${keybinds.map(keybindRowHtml).join('')}</div>
+        <button class="btn-secondary btn-add-value" id="add-keybind">${t('gui.keybind_add')}</button>
+        <datalist id="keybind-actions"></datalist>
+        <div class="keybind-doc" id="keybind-doc"></div>
+        ${keybindWarningsHtml()}
+    `;
+
+    const editor = document.getElementById('keybind-editor');
+    document.getElementById('add-keybind').addEventListener('click', () => {
+        editor.insertAdjacentHTML('beforeend', keybindRowHtml({ prefixes: [], trigger: '', action: '', param: '' }));
+        editor.querySelector('.value-row:last-child .kb-trigger').focus();
+    });
+    editor.addEventListener('click', handleRowButton);
+    editor.addEventListener('input', (e) => updateKeybindRow(e.target.closest('.keybind-row')));
+    editor.addEventListener('focusin', (e) => {
+        if (e.target.classList.contains('kb-action')) showActionDoc(e.target.value.trim());
+    });
+
+    loadKeybindDefaults().then(() => {
+        document.getElementById('keybind-actions').innerHTML = state.actions.map(a =>
+            `<option value="${escapeHtml(a.name)}">${escapeHtml(actionSummary(a))}</option>`).join('');
+        editor.querySelectorAll('.keybind-row').forEach(updateKeybindRow);
+    });
+}
+
+// Conflicts between the saved keybinds, from the problems of the config
+function keybindWarningsHtml() {
+    const warnings = state.problems.filter(p => p.rule.startsWith('keybind-'));
+    if (warnings.length === 0) return '';
+    return `
+        <h3 class="keybind-warnings-title">${t('gui.keybind_warnings')}</h3>
+        <ul class="problems-list keybind-warnings">${warnings.map(p => `
+            <li class="problem problem-${escapeHtml(p.severity)}">
+                <span class="problem-severity">${escapeHtml(t('gui.severity.' + p.severity))}</span>
+                <span class="problem-message">${escapeHtml(p.message)}</span>
+                <span class="problem-location">${escapeHtml(`${p.file}:${p.line}`)}</span>
+            </li>`).join('')}
+        </ul>`;
+}
+
+// The first paragraph of the docs of an action, like schema.Action.Summary
+function actionSummary(action) {
+    return action.doc.split('\n\n')[0].split(/\s+/).join(' ').trim();
+}
+
+function showActionDoc(name) {
+    const action = (state.actions || []).find(a => a.name === name);
+    document.getElementById('keybind-doc').textContent = action ? `${action.name}: ${action.doc}` : '';
+}
+
+// Note the default bindings a row replaces, like schema.Keybind.Shadows
+function updateKeybindRow(row) {
+    const note = row && row.querySelector('.kb-shadows');
+    if (!note || !state.defaultKeybinds) return;
+    const trigger = canonicalTrigger(row.querySelector('.kb-trigger').value);
+    const action = row.querySelector('.kb-action').value.trim();
+    const param = row.querySelector('.kb-param').value.trim();
+    const shadowed = state.defaultKeybinds.filter(d =>
+        trigger !== '' && d.canonical === trigger && (d.action !== action || d.param !== param));
+    note.textContent = shadowed.length > 0
+        ? t('gui.keybind_shadows').replace('%s', shadowed.map(d => d.value).join(', '))
+        : '';
+    if (document.activeElement === row.querySelector('.kb-action')) showActionDoc(action);
+}
+
+// Modifier names Ghostty accepts and their canonical name
+const modifierAliases = {
+    shift: 'shift', ctrl: 'ctrl', control: 'ctrl', alt: 'alt', opt: 'alt',
+    option: 'alt', super: 'super', cmd: 'super', command: 'super'
+};
+
+// Like schema.Keybind.CanonicalTrigger: modifiers resolved and ordered,
+// keys in lower case
+function canonicalTrigger(trigger) {
+    if (trigger.trim() === '') return '';
+    return trigger.trim().split('>').map(step => {
+        const mods = new Set();
+        let key = '';
+        for (const part of step.trim().split('+')) {
+            const mod = modifierAliases[part.toLowerCase()];
+            if (mod) mods.add(mod);
+            else key = part.toLowerCase();
+        }
+        return ['shift', 'ctrl', 'alt', 'super'].filter(m => mods.has(m)).concat(key).join('+');
+    }).join('>');
+}
+
+// A row of the keybind table. Values that cannot be parsed are edited as text.
+function keybindRowHtml(kb) {
+    if (kb.error) {
+        return `
+            <div class="value-row keybind-row keybind-raw" title="${escapeHtml(kb.error)}">
+                <input type="text" class="kb-raw" value="${escapeHtml(kb.value)}">${rowButtonsHtml()}
+            </div>`;
+    }
+    const prefixes = keybindPrefixes.map(p => `
+        <label class="kb-prefix" title="${escapeHtml(t('gui.keybind_prefix.' + p))}">
+            <input type="checkbox" value="${p}" ${kb.prefixes.includes(p) ? 'checked' : ''}>${p}
+        </label>`).join('');
+    return `
+        <div class="value-row keybind-row">
+            <span class="kb-prefixes">${prefixes}</span>
+            <input type="text" class="kb-trigger" value="${escapeHtml(kb.trigger)}" placeholder="ctrl+a>n">
+            <input type="text" class="kb-action" value="${escapeHtml(kb.action)}" placeholder="new_tab" list="keybind-actions">
+            <input type="text" class="kb-param" value="${escapeHtml(kb.param)}" placeholder="-">${rowButtonsHtml()}
+            <span class="kb-shadows"></span>
+        </div>`;
+}
+
+// Write a row of the keybind table as a keybind value, like schema.Keybind.String
+function keybindRowValue(row) {
+    const raw = row.querySelector('.kb-raw');
+    if (raw) return raw.value.trim();
+
+    const trigger = row.querySelector('.kb-trigger').value.trim();
+    const action = row.querySelector('.kb-action').value.trim();
+    const param = row.querySelector('.kb-param').value.trim();
+    if (!trigger) return action === 'clear' ? action : '';
+    if (!action) return '';
+    const prefixes = Array.from(row.querySelectorAll('.kb-prefix input:checked')).map(input => input.value + ':');
+    return prefixes.join('') + trigger + '=' + action + (param ? ':' + param : '');
+}
+
 async function renderFontPicker(container, currentValue) {
     container.innerHTML = `<div class="loading">${t('gui.loading_fonts')}</div>`;
 
@@ -393,6 +924,135 @@ async function renderFontPicker(container, currentValue) {
     }
 }
 
+// Theme picker with one slot, or a light and a dark slot for
+// "light:Name,dark:Name" values
+async function renderThemePicker(container, currentValue) {
+    container.innerHTML = `<div class="loading">${t('gui.loading_themes')}</div>`;
+    state.themeValue = parseThemeValue(currentValue);
+    state.themeSlot = 'light';
+
+    try {
+        const themes = await loadThemes();
+
+        let html = `
+            <div class="theme-slots">
+                <label class="theme-paired"><input type="checkbox" id="theme-paired" ${state.themeValue.paired ? 'checked' : ''}> ${t('gui.theme_paired')}</label>
+                <button class="theme-slot" data-slot="light"></button>
+                <button class="theme-slot" data-slot="dark"></button>
+            </div>
+            <input type="text" class="font-filter" id="theme-filter" placeholder="${t('gui.filter_themes')}" value="">`;
+        html += '<div class="theme-list" id="theme-list">';
+        for (const theme of themes) {
+            html += themeCardHtml(theme, false);
+        }
+        html += '</div>';
+        container.innerHTML = html;
+        renderThemeSlots(container);
+
+        const selected = container.querySelector('.theme-option.selected');
+        if (selected) selected.scrollIntoView({ block: 'nearest' });
+
+        document.getElementById('theme-filter').addEventListener('input', (e) => {
+            const filter = e.target.value.toLowerCase();
+            container.querySelectorAll('.theme-option').forEach(opt => {
+                opt.style.display = opt.dataset.theme.toLowerCase().includes(filter) ? '' : 'none';
+            });
+        });
+
+        document.getElementById('theme-paired').addEventListener('change', (e) => {
+            state.themeValue.paired = e.target.checked;
+            if (!e.target.checked) state.themeValue.dark = state.themeValue.light;
+            state.themeSlot = 'light';
+            renderThemeSlots(container);
+        });
+
+        container.querySelector('.theme-slots').addEventListener('click', (e) => {
+            const slot = e.target.closest('.theme-slot');
+            if (!slot) return;
+            state.themeSlot = slot.dataset.slot;
+            renderThemeSlots(container);
+        });
+
+        document.getElementById('theme-list').addEventListener('click', (e) => {
+            const card = e.target.closest('.theme-option');
+            if (!card) return;
+            const name = card.dataset.theme;
+            if (!state.themeValue.paired) {
+                state.themeValue.light = state.themeValue.dark = name;
+            } else {
+                state.themeValue[state.themeSlot] = name;
+            }
+            renderThemeSlots(container);
+        });
+    } catch (error) {
+        container.innerHTML = `<div class="no-results">${t('gui.error.load_themes')}</div>`;
+    }
+}
+
+// Show the themes in the slots and mark the theme of the active slot
+function renderThemeSlots(container) {
+    const value = state.themeValue;
+    container.querySelectorAll('.theme-slot').forEach(slot => {
+        const name = value[slot.dataset.slot];
+        slot.innerHTML = `${t('gui.theme_' + slot.dataset.slot)}: <strong>${escapeHtml(name || '-')}</strong>`;
+        slot.classList.toggle('active', slot.dataset.slot === state.themeSlot);
+        slot.classList.toggle('hidden', !value.paired);
+    });
+    const current = value.paired ? value[state.themeSlot] : value.light;
+    container.querySelectorAll('.theme-option').forEach(card => {
+        card.classList.toggle('selected', card.dataset.theme === current);
+    });
+}
+
+// Parse a theme value like schema.ParseThemeValue. Values it cannot
+// read are kept as a single theme name.
+function parseThemeValue(value) {
+    value = value.trim();
+    if (!value.startsWith('light:') && !value.startsWith('dark:')) {
+        return { light: value, dark: value, paired: false };
+    }
+    const pair = { light: '', dark: '', paired: true, darkFirst: value.startsWith('dark:') };
+    for (const part of value.split(',')) {
+        const i = part.indexOf(':');
+        const mode = part.slice(0, i).trim();
+        if (i < 0 || (mode !== 'light' && mode !== 'dark') || pair[mode]) {
+            return { light: value, dark: value, paired: false };
+        }
+        pair[mode] = part.slice(i + 1).trim();
+    }
+    return pair;
+}
+
+// Format a theme value like schema.ThemeValue.String
+function formatThemeValue(value) {
+    if (!value.paired) {
+        return value.light;
+    }
+    return value.darkFirst
+        ? `dark:${value.dark},light:${value.light}`
+        : `light:${value.light},dark:${value.dark}`;
+}
+
+// A theme with its palette and a sample prompt drawn in its colors
+function themeCardHtml(theme, selected) {
+    const color = (c, fallback) => escapeHtml(c || fallback);
+    const p = theme.palette || [];
+    const fg = color(theme.foreground, '#ffffff');
+    const swatches = p.map(c => `<span class="theme-swatch" style="background: ${color(c, 'transparent')}"></span>`).join('');
+    return `
+        <div class="theme-option ${selected ? 'selected' : ''}" data-theme="${escapeHtml(theme.name)}">
+            <div class="theme-header">
+                <span class="theme-name">${escapeHtml(theme.name)}</span>
+                <span class="theme-source">${escapeHtml(theme.source)}</span>
+            </div>
+            <div class="theme-preview" style="background: ${color(theme.background, '#000000')}; color: ${fg};">
+                <div><span style="color: ${color(p[2], fg)}">user@ghostty</span>:<span style="color: ${color(p[4], fg)}">~/src</span>$ ls<span class="theme-cursor" style="background: ${color(theme.cursor, fg)}"></span></div>
+                <div><span style="color: ${color(p[1], fg)}">error</span> <span style="color: ${color(p[3], fg)}">warning</span> <span style="color: ${color(p[5], fg)}">info</span> <span style="color: ${color(p[6], fg)}">done</span></div>
+            </div>
+            <div class="theme-palette">${swatches}</div>
+        </div>`;
+}
+
 async function saveCurrentEdit() {
     if (!state.currentOption) return;
 
@@ -400,6 +1060,21 @@ async function saveCurrentEdit() {
     const option = state.currentOption;
     const container = document.getElementById('modal-input-container');
 
+    if (option.repeatable) {
+        const values = option.type === 'keybind'
+            ? Array.from(container.querySelectorAll('.keybind-row')).map(keybindRowValue).filter(v => v !== '')
+            : Array.from(container.querySelectorAll('.value-row input'))
+                .map(input => input.value.trim())
+                .filter(v => v !== '');
+        try {
+            await saveConfigValues(option.key, values);
+            closeModal();
+        } catch (error) {
+            showStatus(t('gui.error.save_prefix') + error.message, true);
+        }
+        return;
+    }
+
     switch (option.type) {
         case 'color':
             value = document.getElementById('custom-color-hex').value;
@@ -408,6 +1083,13 @@ async function saveCurrentEdit() {
             const selected = container.querySelector('.font-option.selected');
             value = selected ? selected.dataset.font : '';
             break;
+        case 'theme':
+            if (state.themeValue.paired && (!state.themeValue.light || !state.themeValue.dark)) {
+                showStatus(t('gui.theme_pair_incomplete'), true);
+                return;
+            }
+            value = formatThemeValue(state.themeValue);
+            break;
         default:
             value = document.getElementById('edit-value').value;
     }
@@ -420,6 +1102,214 @@ async function saveCurrentEdit() {
     }
 }
 
+// Changes view: how the config differs from the defaults or another file
+async function openChanges() {
+    document.getElementById('changes-modal').classList.remove('hidden');
+    await showChanges();
+}
+
+async function showChanges() {
+    const list = document.getElementById('changes-list');
+    const file = document.getElementById('changes-file').value.trim();
+    list.innerHTML = `<div class="loading">${t('gui.loading')}</div>`;
+
+    const response = await fetch('/api/changes' + (file ? `?file=${encodeURIComponent(file)}` : ''));
+    if (!response.ok) {
+        list.innerHTML = `<div class="no-results">${escapeHtml(t('gui.error.changes') + ': ' + await response.text())}</div>`;
+        return;
+    }
+    const data = await response.json();
+    if (data.changes.length === 0) {
+        list.innerHTML = `<div class="no-results">${t('gui.changes_none')}</div>`;
+        return;
+    }
+
+    const fromName = data.from || t('cli.defaults');
+    let html = `<table class="changes-table"><thead><tr>
+        <th>${t('gui.changes_key')}</th><th>${escapeHtml(fromName)}</th><th>${escapeHtml(data.to)}</th>
+    </tr></thead><tbody>`;
+    let section = '';
+    for (const change of data.changes) {
+        if (change.section !== section) {
+            section = change.section;
+            html += `<tr class="changes-section"><td colspan="3">${escapeHtml(translateSection(section))}</td></tr>`;
+        }
+        html += `<tr>
+            <td class="option-key">${escapeHtml(change.key)}</td>
+            <td class="diff-del">${changeValuesHtml(change.from, change.to)}</td>
+            <td class="diff-add">${changeValuesHtml(change.to, change.from)}</td>
+        </tr>`;
+    }
+    list.innerHTML = html + '</tbody></table>';
+}
+
+// Values of one side, with those the other side also has dimmed
+function changeValuesHtml(values, other) {
+    if (values.length === 0) return '<span class="changes-empty">-</span>';
+    return values.map(v => {
+        const cls = other.includes(v) ? 'changes-same' : '';
+        return `<div class="${cls}">${escapeHtml(v)}</div>`;
+    }).join('');
+}
+
+function closeChanges() {
+    document.getElementById('changes-modal').classList.add('hidden');
+}
+
+// What's new: options added, removed or given a new default since
+// another Ghostty version, with those set in the config highlighted
+async function openWhatsNew() {
+    document.getElementById('whatsnew-modal').classList.remove('hidden');
+    await showWhatsNew('');
+}
+
+async function showWhatsNew(from) {
+    const list = document.getElementById('whatsnew-list');
+    list.innerHTML = `<div class="loading">${t('gui.loading')}</div>`;
+
+    const response = await fetch('/api/schema/changes' + (from ? `?from=${encodeURIComponent(from)}` : ''));
+    if (!response.ok) {
+        list.innerHTML = `<div class="no-results">${escapeHtml(t('gui.error.whatsnew') + ': ' + await response.text())}</div>`;
+        return;
+    }
+    const data = await response.json();
+
+    const select = document.getElementById('whatsnew-from');
+    select.innerHTML = data.versions
+        .filter(v => v !== data.to)
+        .map(v => `<option value="${escapeHtml(v)}" ${v === data.from ? 'selected' : ''}>${escapeHtml(v)}</option>`)
+        .join('');
+    select.disabled = !data.from;
+
+    if (!data.from) {
+        list.innerHTML = `<div class="no-results">${t('gui.whatsnew_no_previous')}</div>`;
+        return;
+    }
+    if (data.changes.length === 0) {
+        list.innerHTML = `<div class="no-results">${t('gui.whatsnew_none')}</div>`;
+        return;
+    }
+
+    let html = '';
+    if (data.affected.length > 0) {
+        html += `<div class="whatsnew-summary">${escapeHtml(t('gui.whatsnew_affected').replace('%d', data.affected.length))}</div>`;
+    }
+    html += `<table class="changes-table"><thead><tr>
+        <th>${t('gui.changes_key')}</th><th>Ghostty ${escapeHtml(data.from)}</th><th>Ghostty ${escapeHtml(data.to)}</th>
+    </tr></thead><tbody>`;
+    let section = '';
+    for (const change of data.changes) {
+        if (change.section !== section) {
+            section = change.section;
+            html += `<tr class="changes-section"><td colspan="3">${escapeHtml(translateSection(section))}</td></tr>`;
+        }
+        const cls = data.affected.includes(change.key) ? 'whatsnew-affected' : '';
+        const from = change.kind === 'added' ? `<span class="changes-empty">${t('gui.whatsnew_added')}</span>` : escapeHtml(change.from);
+        const to = change.kind === 'removed' ? `<span class="changes-empty">${t('gui.whatsnew_removed')}</span>` : escapeHtml(change.to);
+        html += `<tr class="${cls}">
+            <td class="option-key">${escapeHtml(change.key)}</td>
+            <td class="diff-del">${from}</td>
+            <td class="diff-add">${to}</td>
+        </tr>`;
+    }
+    list.innerHTML = html + '</tbody></table>';
+}
+
+function closeWhatsNew() {
+    document.getElementById('whatsnew-modal').classList.add('hidden');
+}
+
+// History panel
+async function openHistory() {
+    const list = document.getElementById('history-list');
+    state.selectedSnapshot = null;
+    document.getElementById('history-diff').classList.add('hidden');
+    document.getElementById('history-restore').disabled = true;
+    list.innerHTML = `<div class="loading">${t('gui.loading')}</div>`;
+    document.getElementById('history-modal').classList.remove('hidden');
+
+    try {
+        const response = await fetch('/api/history');
+        if (!response.ok) throw new Error(await response.text());
+        const data = await response.json();
+
+        if (!data.enabled) {
+            list.innerHTML = `<div class="no-results">${t('gui.history_disabled')}</div>`;
+            return;
+        }
+        if (data.snapshots.length === 0) {
+            list.innerHTML = `<div class="no-results">${t('gui.history_empty')}</div>`;
+            return;
+        }
+
+        list.innerHTML = data.snapshots.map(snap => `
+            <div class="history-item" data-id="${escapeHtml(snap.id)}">
+                <span class="history-time">${escapeHtml(new Date(snap.time).toLocaleString())}</span>
+                <span class="history-file">${escapeHtml(snap.file)}</span>
+                <span class="history-size">${snap.size} B</span>
+            </div>
+        `).join('');
+    } catch (error) {
+        list.innerHTML = `<div class="no-results">${t('gui.error.history')}</div>`;
+    }
+}
+
+async function showSnapshot(id) {
+    const response = await fetch(`/api/history?id=${encodeURIComponent(id)}`);
+    if (!response.ok) {
+        showStatus(t('gui.error.history'), true);
+        return;
+    }
+    const data = await response.json();
+    state.selectedSnapshot = id;
+
+    document.querySelectorAll('.history-item').forEach(item => {
+        item.classList.toggle('selected', item.dataset.id === id);
+    });
+
+    const diffEl = document.getElementById('history-diff');
+    diffEl.innerHTML = data.diff ? renderDiff(data.diff) : escapeHtml(t('gui.history_identical'));
+    diffEl.classList.remove('hidden');
+    document.getElementById('history-restore').disabled = !data.diff;
+}
+
+// Color a unified diff line by line
+function renderDiff(diff) {
+    return diff.split('\n').map(line => {
+        let cls = '';
+        if (line.startsWith('@@')) cls = 'diff-hunk';
+        else if (line.startsWith('+')) cls = 'diff-add';
+        else if (line.startsWith('-')) cls = 'diff-del';
+        return `<span class="${cls}">${escapeHtml(line)}</span>`;
+    }).join('\n');
+}
+
+async function restoreSnapshot() {
+    const id = state.selectedSnapshot;
+    if (!id || !confirm(t('gui.history_confirm'))) return;
+
+    try {
+        const response = await fetch('/api/history/restore', {
+            method: 'POST',
+            headers: { 'Content-Type': 'application/json' },
+            body: JSON.stringify({ id })
+        });
+        if (!response.ok) throw new Error(await response.text());
+
+        await Promise.all([loadOptions(), loadConfigPath()]);
+        renderOptions();
+        renderUndoState();
+        closeHistory();
+        showStatus(t('gui.history_restored'));
+    } catch (error) {
+        showStatus(t('gui.error.save_prefix') + error.message, true);
+    }
+}
+
+function closeHistory() {
+    document.getElementById('history-modal').classList.add('hidden');
+}
+
 // Event listeners
 function setupEventListeners() {
     // Search
@@ -443,6 +1333,11 @@ function setupEventListeners() {
 
     // Option click
     document.getElementById('options').addEventListener('click', (e) => {
+        const reset = e.target.closest('.reset-btn');
+        if (reset) {
+            resetOption(reset.dataset.key);
+            return;
+        }
         const card = e.target.closest('.option-card');
         if (card) {
             const key = card.dataset.key;
@@ -458,6 +1353,51 @@ function setupEventListeners() {
     document.getElementById('modal-save').addEventListener('click', saveCurrentEdit);
     document.querySelector('.modal-backdrop').addEventListener('click', closeModal);
 
+    // Undo / redo
+    document.getElementById('undo-btn').addEventListener('click', () => stepJournal('undo'));
+    document.getElementById('redo-btn').addEventListener('click', () => stepJournal('redo'));
+
+    // History panel
+    document.getElementById('history-btn').addEventListener('click', openHistory);
+    document.getElementById('history-close').addEventListener('click', closeHistory);
+    document.getElementById('history-restore').addEventListener('click', restoreSnapshot);
+    document.querySelector('#history-modal .modal-backdrop').addEventListener('click', closeHistory);
+    document.getElementById('history-list').addEventListener('click', (e) => {
+        const item = e.target.closest('.history-item');
+        if (item) showSnapshot(item.dataset.id);
+    });
+
+    // Changes view
+    document.getElementById('changes-btn').addEventListener('click', openChanges);
+    document.getElementById('changes-close').addEventListener('click', closeChanges);
+    document.getElementById('changes-compare').addEventListener('click', showChanges);
+    document.querySelector('#changes-modal .modal-backdrop').addEventListener('click', closeChanges);
+    document.getElementById('changes-file').addEventListener('keydown', (e) => {
+        if (e.key === 'Enter') showChanges();
+    });
+
+    // What's new
+    document.getElementById('whatsnew-btn').addEventListener('click', openWhatsNew);
+    document.getElementById('whatsnew-close').addEventListener('click', closeWhatsNew);
+    document.querySelector('#whatsnew-modal .modal-backdrop').addEventListener('click', closeWhatsNew);
+    document.getElementById('whatsnew-from').addEventListener('change', (e) => showWhatsNew(e.target.value));
+
+    // Problems panel
+    document.getElementById('problems-btn').addEventListener('click', () => {
+        document.getElementById('problems').classList.toggle('hidden');
+    });
+    document.getElementById('problems-close').addEventListener('click', () => {
+        document.getElementById('problems').classList.add('hidden');
+    });
+
+    // Conflicts with changes made on disk
+    document.getElementById('conflicts-list').addEventListener('click', (e) => {
+        const btn = e.target.closest('button[data-keep]');
+        if (!btn) return;
+        const item = btn.closest('.conflict-item');
+        resolveConflict(item.dataset.file, item.dataset.key, btn.dataset.keep);
+    });
+
     // Language switcher
     document.getElementById('lang-switcher').addEventListener('change', (e) => {
         if (e.target.id === 'lang-select') {
@@ -481,10 +1421,16 @@ function setupEventListeners() {
     document.addEventListener('keydown', (e) => {
         if (e.key === 'Escape') {
             closeModal();
+            closeHistory();
+            closeWhatsNew();
         }
         if (e.key === 'Enter' && !document.getElementById('modal').classList.contains('hidden')) {
             saveCurrentEdit();
         }
+        if ((e.ctrlKey || e.metaKey) && e.key.toLowerCase() === 'z' && document.activeElement.tagName !== 'INPUT') {
+            e.preventDefault();
+            stepJournal(e.shiftKey ? 'redo' : 'undo');
+        }
         if (e.key === '/' && document.activeElement.tagName !== 'INPUT') {
             const search = document.getElementById('search');
             if (search) {
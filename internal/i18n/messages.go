@@ -14,30 +14,106 @@ var messagesEN = map[string]string{
 	"error.gui":               "Error running GUI server: %v",
 
 	// TUI
-	"tui.search":             "Search: ",
-	"tui.filter":             "Filter: %s (ESC to clear)",
-	"tui.new_value":          "New value: ",
-	"tui.placeholder":        "Enter value...",
-	"tui.search_placeholder": "Search...",
-	"tui.select_color":       "Select Color: %s",
-	"tui.select_font":        "Select Font: %s",
-	"tui.current":            "Current: %s %s",
-	"tui.custom":             "Custom: ",
-	"tui.custom_color":       "Custom color...",
-	"tui.no_fonts":           "No fonts match filter",
-	"tui.default":            "(default)",
+	"tui.search":                 "Search: ",
+	"tui.filter":                 "Filter: %s (ESC to clear)",
+	"tui.new_value":              "New value: ",
+	"tui.placeholder":            "Enter value...",
+	"tui.search_placeholder":     "Search...",
+	"tui.select_color":           "Select Color: %s",
+	"tui.select_font":            "Select Font: %s",
+	"tui.select_theme":           "Select Theme: %s",
+	"tui.select_value":           "Select Value: %s",
+	"tui.current":                "Current: %s %s",
+	"tui.custom":                 "Custom: ",
+	"tui.custom_color":           "Custom color...",
+	"tui.no_fonts":               "No fonts match filter",
+	"tui.no_themes":              "No themes match filter",
+	"tui.theme_light":            "Light",
+	"tui.theme_dark":             "Dark",
+	"tui.default":                "(default)",
+	"tui.values":                 "Values: %s",
+	"tui.values_count":           "[%d values]",
+	"tui.no_values":              "No values. Press a to add one.",
+	"tui.keybind_prefixes":       "PREFIXES",
+	"tui.keybind_trigger":        "KEYS",
+	"tui.keybind_action":         "ACTION",
+	"tui.keybind_param":          "PARAMETER",
+	"tui.keybind_invalid":        "[invalid]",
+	"tui.keybind_trigger_input":  "Keys:   ",
+	"tui.keybind_action_input":   "Action: ",
+	"tui.keybind_shadows":        "replaces default %s",
+	"tui.keybind_duplicate":      "replaces %d.",
+	"tui.keybind_prefix":         "shares a key sequence prefix with %d., which stops working",
+	"tui.keybind_prefix_default": "shares a key sequence prefix with default %s, which stops working",
+	"tui.defined_in":             "Defined in %s",
+	"tui.problems":               "Problem %d/%d: %s",
+	"tui.conflicts":              "Config changed on disk",
+	"tui.conflicts_desc":         "These options were changed both here and by another program. Choose which value to keep.",
+	"tui.conflict_mine":          "mine:  ",
+	"tui.conflict_theirs":        "disk:  ",
+	"tui.conflict_base":          "before:",
+	"tui.deprecated":             "Deprecated: %s",
+	"tui.deprecated_tag":         "[deprecated]",
+	"tui.platform_only":          "%s only; ignored on this platform",
+	"tui.since":                  "Since Ghostty %s",
 
 	// TUI help
-	"help.main":   "j/k: move | enter/space: toggle/edit | tab: expand all | /: search | q: quit",
-	"help.edit":   "enter: save | esc: cancel",
-	"help.search": "enter: apply | esc: cancel",
-	"help.color":  "j/k: move | enter: select | esc: cancel",
-	"help.font":   "j/k: move | enter: select | type to filter | esc: cancel (%d fonts)",
+	"help.main":      "j/k: move | enter/space: toggle/edit | x: reset to default | tab: expand all | /: search | u/ctrl+r: undo/redo | p: next problem | q: quit",
+	"help.edit":      "enter: save | esc: cancel",
+	"help.search":    "enter: apply | esc: cancel",
+	"help.color":     "j/k: move | enter: select | esc: cancel",
+	"help.choice":    "j/k: move | enter: select | esc: cancel",
+	"help.font":      "j/k: move | enter: select | type to filter | esc: cancel (%d fonts)",
+	"help.theme":     "j/k: move | enter: select | ctrl+p: light/dark pair | tab: switch slot | type to filter | esc: cancel (%d themes)",
+	"help.values":    "j/k: move | enter: edit | a: add | d: delete | K/J: reorder | u/ctrl+r: undo/redo | esc: back",
+	"help.keybind":   "enter: next field / save | tab: complete action / switch field | ctrl+n/ctrl+p: next/previous action | esc: cancel | keys: [global:|all:|unconsumed:|performable:]ctrl+a>n | action: name[:param]",
+	"help.conflicts": "j/k: move | m: keep mine | t: take disk version",
 
 	// Messages
-	"msg.saved":         "Saved: %s = %s",
-	"msg.error":         "Error: %v",
-	"msg.loading_fonts": "Error loading fonts: %v",
+	"msg.saved":              "Saved: %s = %s",
+	"msg.error":              "Error: %v",
+	"msg.loading_fonts":      "Error loading fonts: %v",
+	"msg.loading_themes":     "Error loading themes: %v",
+	"msg.removed":            "Removed: %s = %s",
+	"msg.moved":              "Moved: %s = %s",
+	"msg.reset":              "Reset to default: %s",
+	"msg.conflicts":          "Config changed on disk: %d conflicting options",
+	"msg.invalid":            "Not saved, Ghostty rejects it: %v",
+	"msg.bad_value":          "Not saved: %v",
+	"msg.conflicts_resolved": "Conflicts resolved and saved",
+	"msg.external_changes":   "Merged changes another program made to %s",
+	"msg.undone":             "Undone: %s",
+	"msg.redone":             "Redone: %s",
+	"msg.nothing_to_undo":    "Nothing to undo",
+	"msg.nothing_to_redo":    "Nothing to redo",
+
+	// CLI
+	"cli.backups_disabled":   "Backups are disabled (-backups=0)",
+	"cli.no_snapshots":       "No snapshots yet",
+	"cli.identical":          "Snapshot is identical to the current file",
+	"cli.restored":           "Restored %s from snapshot %s",
+	"cli.schema_fallback":    "checking against the %s",
+	"cli.validate_summary":   "%d errors, %d warnings",
+	"cli.defaults":           "(defaults)",
+	"cli.unknown_key":        "Unknown option: %s",
+	"cli.not_repeatable":     "%s takes a single value",
+	"cli.add_not_repeatable": "%s cannot be repeated; set it without -add to replace its value",
+	"cli.conflict":           "%s: %s was changed on disk at the same time; nothing written",
+	"cli.schema_no_previous": "No other Ghostty version seen yet; its schema is kept when Ghostty is upgraded",
+	"cli.schema_identical":   "No options were added, removed or changed",
+	"cli.schema_affected":    "! %d options set in %s were removed or have a new default",
+
+	// Schema
+	"schema.origin.ghostty":  "schema of Ghostty %s",
+	"schema.origin.cache":    "schema of Ghostty %s (cached; ghostty not found)",
+	"schema.origin.embedded": "schema of Ghostty %s (built-in snapshot; ghostty not found)",
+	"schema.version_unknown": "unknown version",
+	"type.number":            "number",
+	"type.integer":           "whole number",
+	"type.duration":          "duration, e.g. 750ms or 1h30m",
+	"type.path":              "file path",
+	"platform.macos":         "macOS",
+	"platform.gtk":           "Linux (GTK)",
 
 	// GUI server
 	"gui.browser_failed":     "Failed to open browser: %v",
@@ -49,29 +125,94 @@ var messagesEN = map[string]string{
 	"gui.method_not_allowed": "Method not allowed",
 
 	// GUI frontend
-	"gui.search_placeholder": "Search options...",
-	"gui.exit":               "Exit",
-	"gui.all":                "All",
-	"gui.cancel":             "Cancel",
-	"gui.save":               "Save",
-	"gui.loading":            "Loading options...",
-	"gui.no_options":         "No options found",
-	"gui.loading_fonts":      "Loading fonts...",
-	"gui.filter_fonts":       "Filter fonts...",
-	"gui.custom_label":       "Custom:",
-	"gui.modified":           "modified",
-	"gui.default":            "default",
-	"gui.server_stopped":     "Server stopped. You can close this tab.",
+	"gui.search_placeholder":         "Search options...",
+	"gui.exit":                       "Exit",
+	"gui.all":                        "All",
+	"gui.cancel":                     "Cancel",
+	"gui.save":                       "Save",
+	"gui.loading":                    "Loading options...",
+	"gui.no_options":                 "No options found",
+	"gui.loading_fonts":              "Loading fonts...",
+	"gui.filter_fonts":               "Filter fonts...",
+	"gui.loading_themes":             "Loading themes...",
+	"gui.filter_themes":              "Filter themes...",
+	"gui.theme_paired":               "Separate themes for light and dark mode",
+	"gui.theme_light":                "Light",
+	"gui.theme_dark":                 "Dark",
+	"gui.theme_pair_incomplete":      "Choose a theme for both light and dark mode",
+	"gui.custom_label":               "Custom:",
+	"gui.modified":                   "modified",
+	"gui.default":                    "default",
+	"gui.server_stopped":             "Server stopped. You can close this tab.",
+	"gui.add_value":                  "Add value",
+	"gui.keybind_add":                "Add binding",
+	"gui.keybind_prefixes":           "Prefixes",
+	"gui.keybind_trigger":            "Keys",
+	"gui.keybind_action":             "Action",
+	"gui.keybind_param":              "Parameter",
+	"gui.keybind_shadows":            "Replaces the default %s",
+	"gui.keybind_warnings":           "Keybinding conflicts",
+	"gui.keybind_prefix.global":      "Works while Ghostty is not focused",
+	"gui.keybind_prefix.all":         "Applies to all terminals, not just the focused one",
+	"gui.keybind_prefix.unconsumed":  "Also sends the key to the program",
+	"gui.keybind_prefix.performable": "Consumes the key only if the action can be performed",
+	"gui.values_count":               "%d values",
+	"gui.move_up":                    "Move up",
+	"gui.move_down":                  "Move down",
+	"gui.remove":                     "Remove",
+	"gui.defined_in":                 "Defined in %s",
+	"gui.close":                      "Close",
+	"gui.history":                    "History",
+	"gui.changes":                    "Changes",
+	"gui.changes_file":               "Compare with file (empty: defaults)",
+	"gui.changes_compare":            "Compare",
+	"gui.changes_key":                "Option",
+	"gui.changes_none":               "No differences.",
+	"gui.error.changes":              "Failed to compare",
+	"gui.whatsnew":                   "What's new",
+	"gui.whatsnew_from":              "Compared with Ghostty",
+	"gui.whatsnew_none":              "No options were added, removed or changed.",
+	"gui.whatsnew_no_previous":       "No other Ghostty version seen yet. Its options are kept when Ghostty is upgraded.",
+	"gui.whatsnew_affected":          "%d options set in your config were removed or have a new default.",
+	"gui.whatsnew_added":             "(new)",
+	"gui.whatsnew_removed":           "(removed)",
+	"gui.error.whatsnew":             "Failed to compare versions",
+	"gui.keep_mine":                  "Keep mine",
+	"gui.problems":                   "Problems",
+	"gui.problems_count":             "Problems (%d)",
+	"gui.severity.error":             "Error",
+	"gui.severity.warning":           "Warning",
+	"gui.severity.info":              "Info",
+	"gui.keep_theirs":                "Use disk version",
+	"gui.history_restore":            "Restore",
+	"gui.history_empty":              "No snapshots yet. One is taken before every save.",
+	"gui.history_disabled":           "Backups are disabled (-backups=0).",
+	"gui.history_identical":          "This snapshot is identical to the current file.",
+	"gui.history_confirm":            "Restore this snapshot? The current file is backed up first.",
+	"gui.history_restored":           "Snapshot restored",
+	"gui.undo":                       "Undo",
+	"gui.reset":                      "Reset to default",
+	"gui.redo":                       "Redo",
+	"gui.deprecated":                 "deprecated",
+	"gui.deprecated_note":            "Deprecated: %s",
+	"gui.platform_only":              "%s only",
+	"gui.since":                      "since %s",
+	"gui.unsupported":                "This option does not apply on this platform",
 
 	// GUI errors
 	"gui.error.load_options":     "Failed to load options. Please refresh the page.",
 	"gui.error.load_options_api": "Failed to load options",
 	"gui.error.load_colors":      "Failed to load colors",
 	"gui.error.load_fonts":       "Failed to load fonts",
+	"gui.error.load_themes":      "Failed to load themes",
 	"gui.error.save":             "Failed to save config",
+	"gui.error.invalid":          "Not saved: Ghostty rejects this value",
+	"gui.confirm_invalid":        "Ghostty rejects this value. Save it anyway?",
 	"gui.error.save_prefix":      "Failed to save: ",
 	"gui.error.exit":             "Failed to exit",
 	"gui.error.init":             "Failed to initialize:",
+	"gui.error.history":          "Failed to load history",
+	"gui.error.reset":            "Failed to reset option",
 
 	// Categories
 	"category.font":       "Font",
@@ -239,30 +380,106 @@ var messagesJA = map[string]string{
 	"error.gui":               "GUIサーバー実行エラー: %v",
 
 	// TUI
-	"tui.search":             "検索: ",
-	"tui.filter":             "フィルター: %s (ESCでクリア)",
-	"tui.new_value":          "新しい値: ",
-	"tui.placeholder":        "値を入力...",
-	"tui.search_placeholder": "検索...",
-	"tui.select_color":       "色を選択: %s",
-	"tui.select_font":        "フォントを選択: %s",
-	"tui.current":            "現在: %s %s",
-	"tui.custom":             "カスタム: ",
-	"tui.custom_color":       "カスタムカラー...",
-	"tui.no_fonts":           "一致するフォントがありません",
-	"tui.default":            "(デフォルト)",
+	"tui.search":                 "検索: ",
+	"tui.filter":                 "フィルター: %s (ESCでクリア)",
+	"tui.new_value":              "新しい値: ",
+	"tui.placeholder":            "値を入力...",
+	"tui.search_placeholder":     "検索...",
+	"tui.select_color":           "色を選択: %s",
+	"tui.select_font":            "フォントを選択: %s",
+	"tui.select_theme":           "テーマを選択: %s",
+	"tui.select_value":           "値を選択: %s",
+	"tui.current":                "現在: %s %s",
+	"tui.custom":                 "カスタム: ",
+	"tui.custom_color":           "カスタムカラー...",
+	"tui.no_fonts":               "一致するフォントがありません",
+	"tui.no_themes":              "一致するテーマがありません",
+	"tui.theme_light":            "ライト",
+	"tui.theme_dark":             "ダーク",
+	"tui.default":                "(デフォルト)",
+	"tui.values":                 "値一覧: %s",
+	"tui.values_count":           "[%d 件]",
+	"tui.no_values":              "値がありません。a で追加できます。",
+	"tui.keybind_prefixes":       "プレフィックス",
+	"tui.keybind_trigger":        "キー",
+	"tui.keybind_action":         "アクション",
+	"tui.keybind_param":          "パラメーター",
+	"tui.keybind_invalid":        "[不正]",
+	"tui.keybind_trigger_input":  "キー:       ",
+	"tui.keybind_action_input":   "アクション: ",
+	"tui.keybind_shadows":        "デフォルトの %s を置き換え",
+	"tui.keybind_duplicate":      "%d. を置き換え",
+	"tui.keybind_prefix":         "%d. とキーシーケンスの先頭が重なり、そちらは無効になります",
+	"tui.keybind_prefix_default": "デフォルトの %s とキーシーケンスの先頭が重なり、デフォルトは無効になります",
+	"tui.defined_in":             "定義場所: %s",
+	"tui.problems":               "問題 %d/%d: %s",
+	"tui.conflicts":              "設定ファイルが外部で変更されました",
+	"tui.conflicts_desc":         "以下のオプションはこのエディタと他のプログラムの両方で変更されました。残す値を選んでください。",
+	"tui.conflict_mine":          "こちら:",
+	"tui.conflict_theirs":        "ディスク:",
+	"tui.conflict_base":          "変更前:",
+	"tui.deprecated":             "非推奨: %s",
+	"tui.deprecated_tag":         "[非推奨]",
+	"tui.platform_only":          "%s のみ。このプラットフォームでは使われません",
+	"tui.since":                  "Ghostty %s 以降",
 
 	// TUI help
-	"help.main":   "j/k: 移動 | enter/space: 切替/編集 | tab: 全展開 | /: 検索 | q: 終了",
-	"help.edit":   "enter: 保存 | esc: キャンセル",
-	"help.search": "enter: 適用 | esc: キャンセル",
-	"help.color":  "j/k: 移動 | enter: 選択 | esc: キャンセル",
-	"help.font":   "j/k: 移動 | enter: 選択 | 入力でフィルター | esc: キャンセル (%d フォント)",
+	"help.main":      "j/k: 移動 | enter/space: 切替/編集 | x: デフォルトに戻す | tab: 全展開 | /: 検索 | u/ctrl+r: 元に戻す/やり直す | p: 次の問題 | q: 終了",
+	"help.edit":      "enter: 保存 | esc: キャンセル",
+	"help.search":    "enter: 適用 | esc: キャンセル",
+	"help.color":     "j/k: 移動 | enter: 選択 | esc: キャンセル",
+	"help.choice":    "j/k: 移動 | enter: 選択 | esc: キャンセル",
+	"help.font":      "j/k: 移動 | enter: 選択 | 入力でフィルター | esc: キャンセル (%d フォント)",
+	"help.theme":     "j/k: 移動 | enter: 選択 | ctrl+p: ライト/ダークの組 | tab: 切り替え | 入力でフィルター | esc: キャンセル (%d テーマ)",
+	"help.values":    "j/k: 移動 | enter: 編集 | a: 追加 | d: 削除 | K/J: 並べ替え | u/ctrl+r: 元に戻す/やり直す | esc: 戻る",
+	"help.keybind":   "enter: 次の欄 / 保存 | tab: アクションの補完 / 欄の切り替え | ctrl+n/ctrl+p: 次/前のアクション | esc: キャンセル | キー: [global:|all:|unconsumed:|performable:]ctrl+a>n | アクション: 名前[:パラメーター]",
+	"help.conflicts": "j/k: 移動 | m: こちらを残す | t: ディスクの値を採用",
 
 	// Messages
-	"msg.saved":         "保存しました: %s = %s",
-	"msg.error":         "エラー: %v",
-	"msg.loading_fonts": "フォント読み込みエラー: %v",
+	"msg.saved":              "保存しました: %s = %s",
+	"msg.error":              "エラー: %v",
+	"msg.loading_fonts":      "フォント読み込みエラー: %v",
+	"msg.loading_themes":     "テーマ読み込みエラー: %v",
+	"msg.removed":            "削除しました: %s = %s",
+	"msg.moved":              "移動しました: %s = %s",
+	"msg.reset":              "デフォルトに戻しました: %s",
+	"msg.conflicts":          "設定ファイルが外部で変更されました: %d 件の競合",
+	"msg.invalid":            "Ghostty が受け付けないため保存しませんでした: %v",
+	"msg.bad_value":          "保存しませんでした: %v",
+	"msg.conflicts_resolved": "競合を解決して保存しました",
+	"msg.external_changes":   "他のプログラムによる %s の変更を取り込みました",
+	"msg.undone":             "元に戻しました: %s",
+	"msg.redone":             "やり直しました: %s",
+	"msg.nothing_to_undo":    "元に戻す操作はありません",
+	"msg.nothing_to_redo":    "やり直す操作はありません",
+
+	// CLI
+	"cli.backups_disabled":   "バックアップは無効です (-backups=0)",
+	"cli.no_snapshots":       "スナップショットはまだありません",
+	"cli.identical":          "スナップショットは現在のファイルと同一です",
+	"cli.restored":           "%s をスナップショット %s から復元しました",
+	"cli.schema_fallback":    "%s で検査します",
+	"cli.validate_summary":   "エラー %d 件、警告 %d 件",
+	"cli.defaults":           "(デフォルト)",
+	"cli.unknown_key":        "不明なオプション: %s",
+	"cli.not_repeatable":     "%s には値を1つだけ指定できます",
+	"cli.add_not_repeatable": "%s は繰り返し指定できません。値を置き換えるには -add なしで set してください",
+	"cli.conflict":           "%s: %s がディスク上でも変更されたため、書き込みませんでした",
+	"cli.schema_no_previous": "他の Ghostty のバージョンはまだ記録されていません。Ghostty を更新するとスキーマが保存されます",
+	"cli.schema_identical":   "追加・削除・変更されたオプションはありません",
+	"cli.schema_affected":    "! %d 件のオプション (%s で設定) が削除されたか、デフォルトが変わりました",
+
+	// Schema
+	"schema.origin.ghostty":  "Ghostty %s のスキーマ",
+	"schema.origin.cache":    "Ghostty %s のスキーマ(キャッシュ。ghostty が見つかりません)",
+	"schema.origin.embedded": "Ghostty %s のスキーマ(組み込みスナップショット。ghostty が見つかりません)",
+	"schema.version_unknown": "不明なバージョン",
+	"type.number":            "数値",
+	"type.integer":           "整数",
+	"type.duration":          "時間 (例: 750ms, 1h30m)",
+	"type.path":              "ファイルパス",
+	"platform.macos":         "macOS",
+	"platform.gtk":           "Linux (GTK)",
 
 	// GUI server
 	"gui.browser_failed":     "ブラウザを開けませんでした: %v",
@@ -274,29 +491,94 @@ var messagesJA = map[string]string{
 	"gui.method_not_allowed": "許可されていないメソッドです",
 
 	// GUI frontend
-	"gui.search_placeholder": "オプションを検索...",
-	"gui.exit":               "終了",
-	"gui.all":                "すべて",
-	"gui.cancel":             "キャンセル",
-	"gui.save":               "保存",
-	"gui.loading":            "オプションを読み込み中...",
-	"gui.no_options":         "オプションが見つかりません",
-	"gui.loading_fonts":      "フォントを読み込み中...",
-	"gui.filter_fonts":       "フォントを検索...",
-	"gui.custom_label":       "カスタム:",
-	"gui.modified":           "変更済",
-	"gui.default":            "デフォルト",
-	"gui.server_stopped":     "サーバーが停止しました。このタブを閉じてください。",
+	"gui.search_placeholder":         "オプションを検索...",
+	"gui.exit":                       "終了",
+	"gui.all":                        "すべて",
+	"gui.cancel":                     "キャンセル",
+	"gui.save":                       "保存",
+	"gui.loading":                    "オプションを読み込み中...",
+	"gui.no_options":                 "オプションが見つかりません",
+	"gui.loading_fonts":              "フォントを読み込み中...",
+	"gui.filter_fonts":               "フォントを検索...",
+	"gui.loading_themes":             "テーマを読み込み中...",
+	"gui.filter_themes":              "テーマを検索...",
+	"gui.theme_paired":               "ライトモードとダークモードで別のテーマを使う",
+	"gui.theme_light":                "ライト",
+	"gui.theme_dark":                 "ダーク",
+	"gui.theme_pair_incomplete":      "ライトモードとダークモードの両方のテーマを選んでください",
+	"gui.custom_label":               "カスタム:",
+	"gui.modified":                   "変更済",
+	"gui.default":                    "デフォルト",
+	"gui.server_stopped":             "サーバーが停止しました。このタブを閉じてください。",
+	"gui.add_value":                  "値を追加",
+	"gui.keybind_add":                "キー割り当てを追加",
+	"gui.keybind_prefixes":           "プレフィックス",
+	"gui.keybind_trigger":            "キー",
+	"gui.keybind_action":             "アクション",
+	"gui.keybind_param":              "パラメーター",
+	"gui.keybind_shadows":            "デフォルトの %s を置き換えます",
+	"gui.keybind_warnings":           "キー割り当ての競合",
+	"gui.keybind_prefix.global":      "Ghostty がフォーカスされていなくても動作します",
+	"gui.keybind_prefix.all":         "フォーカス中だけでなくすべてのターミナルに適用します",
+	"gui.keybind_prefix.unconsumed":  "キーをプログラムにも送ります",
+	"gui.keybind_prefix.performable": "アクションを実行できる場合にだけキーを消費します",
+	"gui.values_count":               "%d 件",
+	"gui.move_up":                    "上へ",
+	"gui.move_down":                  "下へ",
+	"gui.remove":                     "削除",
+	"gui.defined_in":                 "定義場所: %s",
+	"gui.close":                      "閉じる",
+	"gui.history":                    "履歴",
+	"gui.changes":                    "変更点",
+	"gui.changes_file":               "比較するファイル (空欄: デフォルト)",
+	"gui.changes_compare":            "比較",
+	"gui.changes_key":                "オプション",
+	"gui.changes_none":               "差分はありません。",
+	"gui.whatsnew":                   "更新内容",
+	"gui.whatsnew_from":              "比較する Ghostty",
+	"gui.whatsnew_none":              "追加・削除・変更されたオプションはありません。",
+	"gui.whatsnew_no_previous":       "他の Ghostty のバージョンはまだ記録されていません。Ghostty を更新するとオプションが保存されます。",
+	"gui.whatsnew_affected":          "設定済みのオプションのうち %d 件が削除されたか、デフォルトが変わりました。",
+	"gui.whatsnew_added":             "(新規)",
+	"gui.whatsnew_removed":           "(削除)",
+	"gui.error.whatsnew":             "バージョンの比較に失敗しました",
+	"gui.error.changes":              "比較に失敗しました",
+	"gui.keep_mine":                  "こちらを残す",
+	"gui.problems":                   "問題",
+	"gui.problems_count":             "問題 (%d)",
+	"gui.severity.error":             "エラー",
+	"gui.severity.warning":           "警告",
+	"gui.severity.info":              "情報",
+	"gui.keep_theirs":                "ディスクの値を採用",
+	"gui.history_restore":            "復元",
+	"gui.history_empty":              "スナップショットはまだありません。保存のたびに作成されます。",
+	"gui.history_disabled":           "バックアップは無効です (-backups=0)。",
+	"gui.history_identical":          "このスナップショットは現在のファイルと同一です。",
+	"gui.history_confirm":            "このスナップショットを復元しますか？現在のファイルは先にバックアップされます。",
+	"gui.history_restored":           "スナップショットを復元しました",
+	"gui.undo":                       "元に戻す",
+	"gui.reset":                      "デフォルトに戻す",
+	"gui.redo":                       "やり直す",
+	"gui.deprecated":                 "非推奨",
+	"gui.deprecated_note":            "非推奨: %s",
+	"gui.platform_only":              "%s のみ",
+	"gui.since":                      "%s 以降",
+	"gui.unsupported":                "このオプションはこのプラットフォームでは使われません",
 
 	// GUI errors
 	"gui.error.load_options":     "オプションの読み込みに失敗しました。ページを更新してください。",
 	"gui.error.load_options_api": "オプションの読み込みに失敗",
 	"gui.error.load_colors":      "色の読み込みに失敗",
 	"gui.error.load_fonts":       "フォントの読み込みに失敗",
+	"gui.error.load_themes":      "テーマの読み込みに失敗",
 	"gui.error.save":             "設定の保存に失敗",
+	"gui.error.invalid":          "保存しませんでした: Ghostty がこの値を受け付けません",
+	"gui.confirm_invalid":        "Ghostty はこの値を受け付けません。それでも保存しますか？",
 	"gui.error.save_prefix":      "保存に失敗: ",
 	"gui.error.exit":             "終了に失敗",
 	"gui.error.init":             "初期化に失敗:",
+	"gui.error.history":          "履歴の読み込みに失敗",
+	"gui.error.reset":            "オプションのリセットに失敗",
 
 	// Categories
 	"category.font":       "フォント",
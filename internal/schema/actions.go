@@ -0,0 +1,82 @@
+package schema
+
+import (
+	"os/exec"
+	"strings"
+)
+
+// Action is a keybind action Ghostty can perform
+type Action struct {
+	Name string `json:"name"`
+	Doc  string `json:"doc"`
+}
+
+// Summary returns the first paragraph of the docs on one line
+func (a Action) Summary() string {
+	paragraph, _, _ := strings.Cut(a.Doc, "\n\n")
+	return strings.Join(strings.Fields(paragraph), " ")
+}
+
+// listActions runs `ghostty +list-actions --docs`, which prints each action
+// name followed by ":" and its docs indented below it
+func listActions() ([]Action, error) {
+	output, err := exec.Command("ghostty", "+list-actions", "--docs").Output()
+	if err != nil {
+		return nil, err
+	}
+	return parseActions(string(output)), nil
+}
+
+// parseActions reads the output of `ghostty +list-actions`,
+// with or without --docs
+func parseActions(output string) []Action {
+	var actions []Action
+	var doc []string
+	flush := func() {
+		if len(actions) > 0 {
+			actions[len(actions)-1].Doc = strings.TrimSpace(strings.Join(doc, "\n"))
+		}
+		doc = nil
+	}
+
+	for _, line := range strings.Split(output, "\n") {
+		if line != "" && line[0] != ' ' && line[0] != '\t' {
+			flush()
+			actions = append(actions, Action{Name: strings.TrimSuffix(strings.TrimSpace(line), ":")})
+			continue
+		}
+		doc = append(doc, strings.TrimPrefix(strings.TrimRight(line, " \t"), "  "))
+	}
+	flush()
+	return actions
+}
+
+// listKeybinds runs `ghostty +list-keybinds --default`, which prints the
+// bindings Ghostty has without a config
+func listKeybinds() ([]Keybind, error) {
+	output, err := exec.Command("ghostty", "+list-keybinds", "--default", "--plain").Output()
+	if err != nil {
+		return nil, err
+	}
+	return parseKeybindList(string(output)), nil
+}
+
+// parseKeybindList reads the output of `ghostty +list-keybinds --plain`.
+// Lines that cannot be parsed are skipped.
+func parseKeybindList(output string) []Keybind {
+	var keybinds []Keybind
+	for _, line := range strings.Split(output, "\n") {
+		line = strings.TrimSpace(line)
+		// Lines read "keybind = super+c=copy_to_clipboard"
+		if key, value, ok := strings.Cut(line, "="); ok && strings.TrimSpace(key) == "keybind" {
+			line = value
+		}
+		if line == "" {
+			continue
+		}
+		if kb, err := ParseKeybind(line); err == nil && len(kb.Sequence) > 0 {
+			keybinds = append(keybinds, kb)
+		}
+	}
+	return keybinds
+}
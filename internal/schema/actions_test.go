@@ -0,0 +1,67 @@
+package schema
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestParseActions(t *testing.T) {
+	output, err := os.ReadFile(filepath.Join("testdata", "list-actions-docs.txt"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	actions := parseActions(string(output))
+
+	var names []string
+	for _, a := range actions {
+		names = append(names, a.Name)
+	}
+	if got := strings.Join(names, ","); got != "ignore,unbind,csi,goto_split,toggle_fullscreen" {
+		t.Fatalf("Unexpected actions %s", got)
+	}
+	if doc := actions[1].Doc; doc != "This action is used to flag that the binding should be removed from\n"+
+		"the set. This should never exist in an active set and `set.put` has an\nassertion to verify this." {
+		t.Errorf("Unexpected docs of unbind %q", doc)
+	}
+	if doc := actions[3].Doc; !strings.HasSuffix(doc, "direction.\n\nValid values are: `previous`, `next`, `up`, `left`, `down`, `right`.") {
+		t.Errorf("Expected the paragraphs of goto_split, got %q", doc)
+	}
+	if summary := actions[3].Summary(); summary != "Focus the split in the given direction." {
+		t.Errorf("Unexpected summary %q", summary)
+	}
+	if summary := actions[0].Summary(); summary != "Ignore this key combination, don't send it to the child process, just black hole it." {
+		t.Errorf("Expected the summary on one line, got %q", summary)
+	}
+
+	// Without --docs, only names are printed
+	if actions := parseActions("ignore\nunbind\n"); len(actions) != 2 || actions[1].Name != "unbind" || actions[1].Doc != "" {
+		t.Errorf("Unexpected actions %+v", actions)
+	}
+}
+
+func TestParseKeybindList(t *testing.T) {
+	output, err := os.ReadFile(filepath.Join("testdata", "list-keybinds-default.txt"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []string
+	for _, kb := range parseKeybindList(string(output)) {
+		got = append(got, kb.String())
+	}
+	expected := []string{
+		"super+page_up=scroll_page_up",
+		"super+shift+left_bracket=previous_tab",
+		"ctrl+shift+plus=increase_font_size:1",
+		"super+==increase_font_size:1",
+		"super+physical:one=goto_tab:1",
+		"global:super+grave_accent=toggle_quick_terminal",
+		"performable:super+c=copy_to_clipboard",
+		"ctrl+a>n=new_window",
+		"ctrl+a>ctrl+shift+x>x=close_surface",
+	}
+	if strings.Join(got, "\n") != strings.Join(expected, "\n") {
+		t.Errorf("Expected keybinds\n%s\ngot\n%s", strings.Join(expected, "\n"), strings.Join(got, "\n"))
+	}
+}
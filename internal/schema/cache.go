@@ -0,0 +1,266 @@
+package schema
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/otiai10/ghostconfig/internal/i18n"
+)
+
+// Names of the cached files inside CacheDir
+const (
+	schemaCacheFile   = "schema.json"
+	fontsCacheFile    = "fonts.json"
+	themesCacheFile   = "themes.json"
+	actionsCacheFile  = "actions.json"
+	keybindsCacheFile = "keybinds.json"
+)
+
+// cacheEntry is cached ghostty output together with the build it came from
+type cacheEntry[T any] struct {
+	Ghostty string `json:"ghostty,omitempty"` // see ghosttyBuild
+	Version string `json:"version,omitempty"`
+	Data    T      `json:"data"`
+}
+
+// Origin tells where the options of a Schema come from
+type Origin string
+
+const (
+	OriginGhostty  Origin = "ghostty"  // the installed Ghostty, possibly via the cache
+	OriginCache    Origin = "cache"    // the cache of an earlier run; ghostty cannot be run
+	OriginEmbedded Origin = "embedded" // the snapshot built into ghostconfig; ghostty cannot be run
+)
+
+// Schema is the set of options of one Ghostty version
+type Schema struct {
+	Options []Option
+	Version string // e.g. "1.1.3", empty if unknown
+	Origin  Origin
+}
+
+// Describe returns a translated line naming the Ghostty version the
+// schema describes and whether it is a fallback
+func (s Schema) Describe() string {
+	version := s.Version
+	if version == "" {
+		version = i18n.T("schema.version_unknown")
+	}
+	return fmt.Sprintf(i18n.T("schema.origin."+string(s.Origin)), version)
+}
+
+// CacheDir returns $XDG_CACHE_HOME/ghostconfig,
+// falling back to ~/.cache/ghostconfig
+func CacheDir() string {
+	if cache := os.Getenv("XDG_CACHE_HOME"); cache != "" {
+		return filepath.Join(cache, "ghostconfig")
+	}
+	home, _ := os.UserHomeDir()
+	return filepath.Join(home, ".cache", "ghostconfig")
+}
+
+// ghosttyBuild identifies the installed Ghostty by its `ghostty --version`
+// output and the modification time of the binary, so that caches are
+// rebuilt after an upgrade even if a development build keeps its version.
+func ghosttyBuild() (build string, err error) {
+	path, err := exec.LookPath("ghostty")
+	if err != nil {
+		return "", err
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		return "", err
+	}
+	version, err := exec.Command(path, "--version").Output()
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%s\n%s", bytes.TrimSpace(version), info.ModTime().UTC().Format(time.RFC3339Nano)), nil
+}
+
+// versionOf returns the version number from the first line of a build,
+// which reads "Ghostty 1.1.3"
+func versionOf(build string) string {
+	line, _, _ := strings.Cut(build, "\n")
+	return strings.TrimSpace(strings.TrimPrefix(line, "Ghostty"))
+}
+
+// readCache reads a cache file written by writeCache
+func readCache[T any](name string) (cacheEntry[T], error) {
+	var entry cacheEntry[T]
+	data, err := os.ReadFile(filepath.Join(CacheDir(), name))
+	if err != nil {
+		return entry, err
+	}
+	err = json.Unmarshal(data, &entry)
+	return entry, err
+}
+
+// writeCache caches data for build. Errors are ignored on purpose:
+// caching is best effort, and a read-only or full home must not stop
+// anything, since the data is listed again on the next run.
+func writeCache[T any](name, build string, data T) {
+	out, err := json.Marshal(cacheEntry[T]{Ghostty: build, Version: versionOf(build), Data: data})
+	if err != nil {
+		return
+	}
+	path := filepath.Join(CacheDir(), name)
+	if os.MkdirAll(filepath.Dir(path), 0755) == nil {
+		os.WriteFile(path, out, 0644)
+	}
+}
+
+// Load returns the options of the installed Ghostty. They are parsed once
+// per Ghostty build and then read from the cache; refresh parses them
+// again and also drops the cached fonts, themes, actions and keybinds.
+// If ghostty cannot be run, the options cached by the last successful
+// run are returned instead, or else the snapshot built into ghostconfig.
+func Load(refresh bool) (Schema, error) {
+	build, err := ghosttyBuild()
+	if err != nil {
+		return fallback(err)
+	}
+	if !refresh {
+		if entry, err := readCache[[]Option](schemaCacheFile); err == nil && entry.Ghostty == build && len(entry.Data) > 0 {
+			saveVersion(build, entry.Data, true)
+			return Schema{Options: entry.Data, Version: entry.Version, Origin: OriginGhostty}, nil
+		}
+	}
+
+	options, err := Parse()
+	if err != nil {
+		return fallback(err)
+	}
+	writeCache(schemaCacheFile, build, options)
+	saveVersion(build, options, false)
+	if refresh {
+		for _, name := range []string{fontsCacheFile, themesCacheFile, actionsCacheFile, keybindsCacheFile} {
+			os.Remove(filepath.Join(CacheDir(), name))
+		}
+	}
+	return Schema{Options: options, Version: versionOf(build), Origin: OriginGhostty}, nil
+}
+
+// fallback returns the cached or embedded schema when ghostty fails with err
+func fallback(err error) (Schema, error) {
+	// LoadFile also reads caches written before they were keyed by build
+	if options, cerr := LoadFile(filepath.Join(CacheDir(), schemaCacheFile)); cerr == nil && len(options) > 0 {
+		entry, _ := readCache[[]Option](schemaCacheFile)
+		return Schema{Options: options, Version: entry.Version, Origin: OriginCache}, nil
+	}
+	if sch, eerr := Embedded(); eerr == nil {
+		return sch, nil
+	}
+	return Schema{}, err
+}
+
+// ListFonts returns the font families ghostty can use. Like Load, the
+// list is cached per Ghostty build, and the cached list is used if
+// ghostty cannot be run. Fonts installed later show up after a refresh.
+func ListFonts() ([]string, error) {
+	build, err := ghosttyBuild()
+	if entry, cerr := readCache[[]string](fontsCacheFile); cerr == nil && (err != nil || entry.Ghostty == build) {
+		return entry.Data, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	fonts, err := listFonts()
+	if err != nil {
+		return nil, err
+	}
+	writeCache(fontsCacheFile, build, fonts)
+	return fonts, nil
+}
+
+// ListThemes returns the themes ghostty can use with their colors.
+// It is cached per Ghostty build like ListFonts, so themes added later
+// show up after a refresh.
+func ListThemes() ([]Theme, error) {
+	build, err := ghosttyBuild()
+	if entry, cerr := readCache[[]Theme](themesCacheFile); cerr == nil && (err != nil || entry.Ghostty == build) {
+		return entry.Data, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	themes, err := listThemes()
+	if err != nil {
+		return nil, err
+	}
+	writeCache(themesCacheFile, build, themes)
+	return themes, nil
+}
+
+// ListActions returns the keybind actions of the installed Ghostty with
+// their docs, cached per Ghostty build like ListFonts
+func ListActions() ([]Action, error) {
+	build, err := ghosttyBuild()
+	if entry, cerr := readCache[[]Action](actionsCacheFile); cerr == nil && (err != nil || entry.Ghostty == build) {
+		return entry.Data, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	actions, err := listActions()
+	if err != nil {
+		return nil, err
+	}
+	writeCache(actionsCacheFile, build, actions)
+	return actions, nil
+}
+
+// ListKeybinds returns the keybinds Ghostty has by default, which
+// differ by platform. It is cached per Ghostty build like ListFonts.
+func ListKeybinds() ([]Keybind, error) {
+	build, err := ghosttyBuild()
+	if entry, cerr := readCache[[]Keybind](keybindsCacheFile); cerr == nil && (err != nil || entry.Ghostty == build) {
+		return entry.Data, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	keybinds, err := listKeybinds()
+	if err != nil {
+		return nil, err
+	}
+	writeCache(keybindsCacheFile, build, keybinds)
+	return keybinds, nil
+}
+
+// LoadFile reads options from a file holding either a cached schema or
+// the output of `ghostty +show-config --default --docs`, so a schema can
+// be kept next to configs that are checked where Ghostty is not installed.
+func LoadFile(path string) ([]Option, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	trimmed := bytes.TrimSpace(data)
+	switch {
+	case bytes.HasPrefix(trimmed, []byte("{")):
+		var entry cacheEntry[[]Option]
+		if err := json.Unmarshal(trimmed, &entry); err != nil {
+			return nil, err
+		}
+		return entry.Data, nil
+	case bytes.HasPrefix(trimmed, []byte("[")):
+		// Caches written before they were keyed by Ghostty build
+		var options []Option
+		if err := json.Unmarshal(trimmed, &options); err != nil {
+			return nil, err
+		}
+		return options, nil
+	}
+	return parseOutput(string(data)), nil
+}
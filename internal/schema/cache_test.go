@@ -0,0 +1,126 @@
+package schema
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"slices"
+	"testing"
+)
+
+// tempCache points CacheDir to a directory that does not exist yet
+func tempCache(t *testing.T) string {
+	cache := filepath.Join(t.TempDir(), "cache")
+	t.Setenv("XDG_CACHE_HOME", cache)
+	return filepath.Join(cache, "ghostconfig")
+}
+
+// fakeGhostty puts a ghostty on PATH whose version and font list are read
+// from $FAKE_GHOSTTY_VERSION and $FAKE_GHOSTTY_FONT
+func fakeGhostty(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("needs a shell script")
+	}
+	dir := t.TempDir()
+	script := `#!/bin/sh
+case "$1" in
+--version) echo "Ghostty $FAKE_GHOSTTY_VERSION" ;;
++list-fonts) printf '%s\n  %s Regular\n' "$FAKE_GHOSTTY_FONT" "$FAKE_GHOSTTY_FONT" ;;
+*) exit 1 ;;
+esac
+`
+	if err := os.WriteFile(filepath.Join(dir, "ghostty"), []byte(script), 0755); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("PATH", dir)
+	t.Setenv("FAKE_GHOSTTY_VERSION", "1.1.3")
+	t.Setenv("FAKE_GHOSTTY_FONT", "JetBrains Mono")
+}
+
+func TestCacheRoundTrip(t *testing.T) {
+	dir := tempCache(t)
+
+	// writeCache creates the missing directory
+	writeCache(fontsCacheFile, "Ghostty 1.1.3\n2025-01-01T00:00:00Z", []string{"Menlo"})
+	if _, err := os.Stat(filepath.Join(dir, fontsCacheFile)); err != nil {
+		t.Fatalf("Expected the cache file to be written, got %v", err)
+	}
+	entry, err := readCache[[]string](fontsCacheFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if entry.Ghostty != "Ghostty 1.1.3\n2025-01-01T00:00:00Z" || entry.Version != "1.1.3" || !slices.Equal(entry.Data, []string{"Menlo"}) {
+		t.Errorf("Unexpected entry %+v", entry)
+	}
+
+	if _, err := readCache[[]string](themesCacheFile); err == nil {
+		t.Error("Expected an error for a missing cache file")
+	}
+	if err := os.WriteFile(filepath.Join(dir, themesCacheFile), []byte("{not json"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := readCache[[]string](themesCacheFile); err == nil {
+		t.Error("Expected an error for a corrupt cache file")
+	}
+}
+
+func TestWriteCacheUnwritable(t *testing.T) {
+	// A file where the cache directory should be makes every write fail
+	base := t.TempDir()
+	if err := os.WriteFile(filepath.Join(base, "ghostconfig"), nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("XDG_CACHE_HOME", base)
+	writeCache(fontsCacheFile, "Ghostty 1.1.3", []string{"Menlo"})
+	if _, err := readCache[[]string](fontsCacheFile); err == nil {
+		t.Error("Expected nothing to be cached")
+	}
+}
+
+func TestListFontsCache(t *testing.T) {
+	dir := tempCache(t)
+	fakeGhostty(t)
+
+	fonts, err := ListFonts()
+	if err != nil || !slices.Equal(fonts, []string{"JetBrains Mono"}) {
+		t.Fatalf("Expected the fonts of ghostty, got %v, %v", fonts, err)
+	}
+
+	// The same build is served from the cache
+	t.Setenv("FAKE_GHOSTTY_FONT", "Fira Code")
+	if fonts, _ := ListFonts(); !slices.Equal(fonts, []string{"JetBrains Mono"}) {
+		t.Errorf("Expected the cached fonts, got %v", fonts)
+	}
+
+	// Another build does not match the cache key
+	t.Setenv("FAKE_GHOSTTY_VERSION", "1.2.0")
+	if fonts, _ := ListFonts(); !slices.Equal(fonts, []string{"Fira Code"}) {
+		t.Errorf("Expected the fonts to be listed again, got %v", fonts)
+	}
+	entry, err := readCache[[]string](fontsCacheFile)
+	if err != nil || entry.Version != "1.2.0" {
+		t.Errorf("Expected the cache to be rewritten for 1.2.0, got %+v, %v", entry, err)
+	}
+
+	// A corrupt cache is replaced
+	if err := os.WriteFile(filepath.Join(dir, fontsCacheFile), []byte("{not json"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("FAKE_GHOSTTY_FONT", "Iosevka")
+	if fonts, _ := ListFonts(); !slices.Equal(fonts, []string{"Iosevka"}) {
+		t.Errorf("Expected the fonts to be listed again, got %v", fonts)
+	}
+	if entry, err := readCache[[]string](fontsCacheFile); err != nil || !slices.Equal(entry.Data, []string{"Iosevka"}) {
+		t.Errorf("Expected the corrupt cache to be rewritten, got %+v, %v", entry, err)
+	}
+
+	// Without ghostty, the cache of any build is used
+	t.Setenv("PATH", t.TempDir())
+	if fonts, err := ListFonts(); err != nil || !slices.Equal(fonts, []string{"Iosevka"}) {
+		t.Errorf("Expected the cached fonts, got %v, %v", fonts, err)
+	}
+	os.Remove(filepath.Join(dir, fontsCacheFile))
+	if _, err := ListFonts(); err == nil {
+		t.Error("Expected an error without ghostty or a cache")
+	}
+}
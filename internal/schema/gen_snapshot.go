@@ -0,0 +1,53 @@
+//go:build ignore
+
+// gen_snapshot writes snapshot.json from the installed Ghostty.
+// Run it with `go generate ./internal/schema` on a machine with the
+// Ghostty release the snapshot should describe.
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/otiai10/ghostconfig/internal/schema"
+)
+
+func main() {
+	version, err := exec.Command("ghostty", "--version").Output()
+	if err != nil {
+		fail(err)
+	}
+	line, _, _ := bytes.Cut(version, []byte("\n"))
+
+	options, err := schema.Parse()
+	if err != nil {
+		fail(err)
+	}
+
+	// Same layout as the schema cache. The build key holds only the
+	// version line, which marks the snapshot as generated from a release.
+	out, err := json.MarshalIndent(struct {
+		Ghostty string          `json:"ghostty"`
+		Version string          `json:"version"`
+		Data    []schema.Option `json:"data"`
+	}{
+		Ghostty: strings.TrimSpace(string(line)),
+		Version: strings.TrimSpace(strings.TrimPrefix(string(line), "Ghostty")),
+		Data:    options,
+	}, "", "  ")
+	if err != nil {
+		fail(err)
+	}
+	if err := os.WriteFile("snapshot.json", append(out, '\n'), 0644); err != nil {
+		fail(err)
+	}
+}
+
+func fail(err error) {
+	fmt.Fprintln(os.Stderr, "gen_snapshot:", err)
+	os.Exit(1)
+}
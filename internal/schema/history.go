@@ -0,0 +1,206 @@
+package schema
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// versionsDir keeps a copy of the schema of every Ghostty version seen,
+// so that schema-diff can show what changed after an upgrade
+const versionsDir = "versions"
+
+// Kinds of OptionChange
+const (
+	ChangeAdded   = "added"
+	ChangeRemoved = "removed"
+	ChangeDefault = "default" // the default value changed
+)
+
+// OptionChange is a difference between the schemas of two Ghostty versions
+type OptionChange struct {
+	Key     string `json:"key"`
+	Section string `json:"section"`
+	Kind    string `json:"kind"`
+	From    string `json:"from,omitempty"` // old default
+	To      string `json:"to,omitempty"`   // new default
+}
+
+// versionFile returns the name of the cached schema of version inside CacheDir
+func versionFile(version string) string {
+	return filepath.Join(versionsDir, strings.ReplaceAll(version, string(filepath.Separator), "_")+".json")
+}
+
+// saveVersion keeps the options of build for later comparisons.
+// If missing is true, options already kept for the version are kept.
+func saveVersion(build string, options []Option, missing bool) {
+	version := versionOf(build)
+	if version == "" {
+		return
+	}
+	if _, err := os.Stat(filepath.Join(CacheDir(), versionFile(version))); missing && err == nil {
+		return
+	}
+	writeCache(versionFile(version), build, options)
+}
+
+// Versions returns the Ghostty versions whose schema is known, from the
+// cache and the built-in snapshot, oldest first. A snapshot that was not
+// generated from a release is left out (see snapshot).
+func Versions() []string {
+	var versions []string
+	entries, _ := os.ReadDir(filepath.Join(CacheDir(), versionsDir))
+	for _, e := range entries {
+		if entry, err := readCache[[]Option](filepath.Join(versionsDir, e.Name())); err == nil && entry.Version != "" {
+			versions = append(versions, entry.Version)
+		}
+	}
+	if sch, ok := release(); ok && !slices.Contains(versions, sch.Version) {
+		versions = append(versions, sch.Version)
+	}
+	slices.SortFunc(versions, CompareVersions)
+	return versions
+}
+
+// LoadVersion returns the known schema of a Ghostty version
+func LoadVersion(version string) (Schema, error) {
+	if entry, err := readCache[[]Option](versionFile(version)); err == nil {
+		return Schema{Options: entry.Data, Version: entry.Version, Origin: OriginCache}, nil
+	}
+	if sch, ok := release(); ok && sch.Version == version {
+		return sch, nil
+	}
+	return Schema{}, errors.New("no schema known for Ghostty " + version)
+}
+
+// Previous returns the newest known schema older than version, or else
+// the newest known schema of another version
+func Previous(version string) (Schema, bool) {
+	versions := slices.DeleteFunc(Versions(), func(v string) bool { return v == version })
+	if len(versions) == 0 {
+		return Schema{}, false
+	}
+	previous := versions[len(versions)-1]
+	for _, v := range versions {
+		if CompareVersions(v, version) < 0 {
+			previous = v
+		}
+	}
+	sch, err := LoadVersion(previous)
+	return sch, err == nil
+}
+
+// CompareVersions orders versions such as "1.1.3" and "1.2.0-dev" by
+// semver rules: by their numbers, then a pre-release such as "-dev"
+// before the release itself. Build metadata after "+" only breaks ties.
+func CompareVersions(a, b string) int {
+	pa, pb := versionNumbers(a), versionNumbers(b)
+	for i := range max(len(pa), len(pb)) {
+		var x, y int
+		if i < len(pa) {
+			x = pa[i]
+		}
+		if i < len(pb) {
+			y = pb[i]
+		}
+		if x != y {
+			return x - y
+		}
+	}
+	if c := comparePrerelease(prerelease(a), prerelease(b)); c != 0 {
+		return c
+	}
+	return strings.Compare(a, b)
+}
+
+func versionNumbers(v string) []int {
+	core, _, _ := strings.Cut(v, "+")
+	core, _, _ = strings.Cut(core, "-")
+	var numbers []int
+	for _, part := range strings.Split(core, ".") {
+		n, _ := strconv.Atoi(part)
+		numbers = append(numbers, n)
+	}
+	return numbers
+}
+
+// prerelease returns the part of v between "-" and "+", if any
+func prerelease(v string) string {
+	v, _, _ = strings.Cut(v, "+")
+	_, pre, _ := strings.Cut(v, "-")
+	return pre
+}
+
+// comparePrerelease compares pre-release parts by semver rules: none sorts
+// after any, dot-separated identifiers are compared in turn, numbers
+// numerically and before words, and more identifiers sort later
+func comparePrerelease(a, b string) int {
+	switch {
+	case a == b:
+		return 0
+	case a == "":
+		return 1
+	case b == "":
+		return -1
+	}
+	ia, ib := strings.Split(a, "."), strings.Split(b, ".")
+	for i := range min(len(ia), len(ib)) {
+		x, xerr := strconv.Atoi(ia[i])
+		y, yerr := strconv.Atoi(ib[i])
+		var c int
+		switch {
+		case xerr == nil && yerr == nil:
+			c = x - y
+		case xerr == nil:
+			c = -1
+		case yerr == nil:
+			c = 1
+		default:
+			c = strings.Compare(ia[i], ib[i])
+		}
+		if c != 0 {
+			return c
+		}
+	}
+	return len(ia) - len(ib)
+}
+
+// Diff returns the options added or removed between two schemas and the
+// options whose default changed, ordered by category and key
+func Diff(from, to []Option) []OptionChange {
+	old := make(map[string]Option, len(from))
+	for _, opt := range from {
+		old[opt.Key] = opt
+	}
+	var changes []OptionChange
+	seen := make(map[string]bool, len(to))
+	for _, opt := range to {
+		seen[opt.Key] = true
+		prev, ok := old[opt.Key]
+		switch {
+		case !ok:
+			changes = append(changes, OptionChange{Key: opt.Key, Kind: ChangeAdded, To: opt.DefaultValue})
+		case prev.DefaultValue != opt.DefaultValue:
+			changes = append(changes, OptionChange{Key: opt.Key, Kind: ChangeDefault, From: prev.DefaultValue, To: opt.DefaultValue})
+		}
+	}
+	for _, opt := range from {
+		if !seen[opt.Key] {
+			changes = append(changes, OptionChange{Key: opt.Key, Kind: ChangeRemoved, From: opt.DefaultValue})
+		}
+	}
+
+	for i := range changes {
+		changes[i].Section = ExtractSection(changes[i].Key)
+	}
+	slices.SortFunc(changes, func(a, b OptionChange) int {
+		if a.Section != b.Section {
+			return CategoryIndex(a.Section) - CategoryIndex(b.Section)
+		}
+		return strings.Compare(a.Key, b.Key)
+	})
+	return changes
+}
@@ -0,0 +1,76 @@
+package schema
+
+import (
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestCompareVersions(t *testing.T) {
+	// In ascending order, as semver orders them
+	ordered := []string{
+		"1.0.0-alpha", "1.0.0-alpha.1", "1.0.0-alpha.beta", "1.0.0-beta", "1.0.0-beta.2",
+		"1.0.0-beta.11", "1.0.0-rc.1", "1.0.0", "1.1.3", "1.2.0-dev", "1.2.0-dev+5ab1d6f", "1.2.0", "1.10.0",
+	}
+	shuffled := slices.Clone(ordered)
+	slices.Reverse(shuffled)
+	slices.SortFunc(shuffled, CompareVersions)
+	if !slices.Equal(shuffled, ordered) {
+		t.Errorf("Expected order %v, got %v", ordered, shuffled)
+	}
+	if CompareVersions("1.2.0-dev", "1.2.0") >= 0 {
+		t.Error("Expected 1.2.0-dev to sort before 1.2.0")
+	}
+	if CompareVersions("1.2.0", "1.2") != strings.Compare("1.2.0", "1.2") {
+		t.Error("Expected versions with the same numbers to be ordered as strings")
+	}
+}
+
+func TestDiff(t *testing.T) {
+	from := []Option{
+		{Key: "font-size", DefaultValue: "13"},
+		{Key: "cursor-style", DefaultValue: "block"},
+		{Key: "gtk-adwaita", DefaultValue: "true"},
+		{Key: "theme"},
+	}
+	to := []Option{
+		{Key: "font-size", DefaultValue: "13"},
+		{Key: "cursor-style", DefaultValue: "bar"},
+		{Key: "theme"},
+		{Key: "window-subtitle", DefaultValue: "false"},
+	}
+
+	var got []string
+	for _, c := range Diff(from, to) {
+		got = append(got, c.Kind+" "+c.Key+" "+c.From+">"+c.To)
+	}
+	expected := []string{"default cursor-style block>bar", "added window-subtitle >false", "removed gtk-adwaita true>"}
+	if strings.Join(got, ", ") != strings.Join(expected, ", ") {
+		t.Errorf("Expected changes %v, got %v", expected, got)
+	}
+}
+
+func TestVersionsSkipsHandWrittenSnapshot(t *testing.T) {
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+	embedded := snapshot
+	t.Cleanup(func() { snapshot = embedded })
+
+	snapshot = []byte(`{"version": "9.9.9", "data": [{"key": "font-size", "default": "13"}]}`)
+	if slices.Contains(Versions(), "9.9.9") {
+		t.Errorf("Expected a snapshot without a build to be left out, got %v", Versions())
+	}
+	if _, err := LoadVersion("9.9.9"); err == nil {
+		t.Error("Expected no schema for the version of a hand-written snapshot")
+	}
+	if _, ok := Previous("10.0.0"); ok {
+		t.Error("Expected no previous schema")
+	}
+
+	snapshot = []byte(`{"ghostty": "Ghostty 9.9.9", "version": "9.9.9", "data": [{"key": "font-size", "default": "13"}]}`)
+	if !slices.Equal(Versions(), []string{"9.9.9"}) {
+		t.Errorf("Expected the generated snapshot, got %v", Versions())
+	}
+	if sch, ok := Previous("10.0.0"); !ok || sch.Version != "9.9.9" || sch.Origin != OriginEmbedded {
+		t.Errorf("Expected the generated snapshot as the previous schema, got %v %v", sch.Version, ok)
+	}
+}
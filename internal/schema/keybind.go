@@ -0,0 +1,298 @@
+package schema
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// Prefixes of a keybind that change where and when it applies
+const (
+	KeybindGlobal      = "global"      // works while Ghostty is not focused
+	KeybindAll         = "all"         // applies to all terminals, not just the focused one
+	KeybindUnconsumed  = "unconsumed"  // the key is also sent to the program
+	KeybindPerformable = "performable" // consumed only if the action can be performed
+)
+
+// KeybindPrefixes lists the keybind prefixes in the order Ghostty documents them
+var KeybindPrefixes = []string{KeybindGlobal, KeybindAll, KeybindUnconsumed, KeybindPerformable}
+
+// KeybindClear is the keybind value that removes all bindings, defaults included
+const KeybindClear = "clear"
+
+// modifierAliases maps the modifier names Ghostty accepts to their canonical name
+var modifierAliases = map[string]string{
+	"shift":   "shift",
+	"ctrl":    "ctrl",
+	"control": "ctrl",
+	"alt":     "alt",
+	"opt":     "alt",
+	"option":  "alt",
+	"super":   "super",
+	"cmd":     "super",
+	"command": "super",
+}
+
+// modifierOrder is the order of the canonical modifier names in Canonical
+var modifierOrder = []string{"shift", "ctrl", "alt", "super"}
+
+// KeyTrigger is one key press of a keybind, such as "ctrl+shift+a".
+// Mods are kept as written.
+type KeyTrigger struct {
+	Mods     []string `json:"mods"`
+	Key      string   `json:"key"`
+	Physical bool     `json:"physical,omitempty"` // written as physical:key
+}
+
+// Keybind is a value of the keybind option,
+// "[prefix:]...trigger[>trigger]...=action[:param]".
+// The value "clear" has no triggers and the action "clear".
+type Keybind struct {
+	Prefixes []string     `json:"prefixes"`
+	Sequence []KeyTrigger `json:"sequence"`
+	Action   string       `json:"action"`
+	Param    string       `json:"param,omitempty"`
+}
+
+// ParseKeybind parses a value of the keybind option
+func ParseKeybind(value string) (Keybind, error) {
+	value = strings.TrimSpace(value)
+	if value == KeybindClear {
+		return Keybind{Action: KeybindClear}, nil
+	}
+
+	kb := Keybind{}
+	rest := value
+	for {
+		prefix, after, ok := strings.Cut(rest, ":")
+		if !ok || !slices.Contains(KeybindPrefixes, prefix) {
+			break
+		}
+		if slices.Contains(kb.Prefixes, prefix) {
+			return Keybind{}, fmt.Errorf("prefix %q given twice in %q", prefix, value)
+		}
+		kb.Prefixes = append(kb.Prefixes, prefix)
+		rest = after
+	}
+
+	eq := triggerEnd(rest)
+	if eq < 0 {
+		return Keybind{}, fmt.Errorf("expected TRIGGER=ACTION, got %q", value)
+	}
+	trigger, action := rest[:eq], strings.TrimSpace(rest[eq+1:])
+
+	for _, step := range strings.Split(trigger, ">") {
+		t, err := ParseKeyTrigger(step)
+		if err != nil {
+			return Keybind{}, fmt.Errorf("%w in %q", err, value)
+		}
+		kb.Sequence = append(kb.Sequence, t)
+	}
+
+	kb.Action, kb.Param, _ = strings.Cut(action, ":")
+	if kb.Action == "" {
+		return Keybind{}, fmt.Errorf("missing action in %q", value)
+	}
+	return kb, nil
+}
+
+// triggerEnd returns the index of the "=" ending the trigger of s. An "="
+// right after "+" or ">", or at the start, is the equals key itself.
+func triggerEnd(s string) int {
+	for i := 1; i < len(s); i++ {
+		if s[i] == '=' && s[i-1] != '+' && s[i-1] != '>' {
+			return i
+		}
+	}
+	return -1
+}
+
+// ParseKeyTrigger parses one key press such as "ctrl+shift+a" or
+// "super+physical:bracket_left"
+func ParseKeyTrigger(s string) (KeyTrigger, error) {
+	var t KeyTrigger
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return t, fmt.Errorf("empty trigger")
+	}
+	// The plus key is written "plus"
+	for _, part := range strings.Split(s, "+") {
+		if _, ok := modifierAliases[strings.ToLower(part)]; ok {
+			t.Mods = append(t.Mods, part)
+			continue
+		}
+		if t.Key != "" {
+			return KeyTrigger{}, fmt.Errorf("trigger %q presses more than one key", s)
+		}
+		if key, ok := strings.CutPrefix(part, "physical:"); ok {
+			t.Physical = true
+			part = key
+		}
+		if part == "" {
+			return KeyTrigger{}, fmt.Errorf("trigger %q has an empty key", s)
+		}
+		t.Key = part
+	}
+	if t.Key == "" {
+		return KeyTrigger{}, fmt.Errorf("trigger %q has no key", s)
+	}
+	return t, nil
+}
+
+// String returns the key press as written in a config
+func (t KeyTrigger) String() string {
+	key := t.Key
+	if t.Physical {
+		key = "physical:" + key
+	}
+	return strings.Join(append(slices.Clone(t.Mods), key), "+")
+}
+
+// Canonical returns the key press with modifier aliases resolved and put
+// in the order shift, ctrl, alt, super, and the key in lower case, so that
+// "Cmd+Shift+T" and "shift+super+t" compare equal
+func (t KeyTrigger) Canonical() string {
+	var parts []string
+	for _, mod := range modifierOrder {
+		if slices.ContainsFunc(t.Mods, func(m string) bool { return modifierAliases[strings.ToLower(m)] == mod }) {
+			parts = append(parts, mod)
+		}
+	}
+	key := strings.ToLower(t.Key)
+	if t.Physical {
+		key = "physical:" + key
+	}
+	return strings.Join(append(parts, key), "+")
+}
+
+// Trigger returns the key sequence of the keybind, such as "ctrl+a>n"
+func (k Keybind) Trigger() string {
+	steps := make([]string, len(k.Sequence))
+	for i, t := range k.Sequence {
+		steps[i] = t.String()
+	}
+	return strings.Join(steps, ">")
+}
+
+// CanonicalTrigger returns the key sequence with every key press in the
+// form of KeyTrigger.Canonical
+func (k Keybind) CanonicalTrigger() string {
+	return strings.Join(k.canonicalSteps(), ">")
+}
+
+// Shadows returns the bindings of defaults that k replaces because they
+// are triggered by the same keys. Defaults k repeats are not included,
+// and neither are defaults an unbind removes, which is what it is for.
+func (k Keybind) Shadows(defaults []Keybind) []Keybind {
+	if len(k.Sequence) == 0 || k.Action == "unbind" {
+		return nil
+	}
+	trigger := k.CanonicalTrigger()
+	var shadowed []Keybind
+	for _, d := range defaults {
+		if d.CanonicalTrigger() == trigger && (d.Action != k.Action || d.Param != k.Param) {
+			shadowed = append(shadowed, d)
+		}
+	}
+	return shadowed
+}
+
+// String returns the keybind as written in a config
+func (k Keybind) String() string {
+	if len(k.Sequence) == 0 {
+		return k.Action
+	}
+	var b strings.Builder
+	for _, p := range k.Prefixes {
+		b.WriteString(p + ":")
+	}
+	b.WriteString(k.Trigger() + "=" + k.Action)
+	if k.Param != "" {
+		b.WriteString(":" + k.Param)
+	}
+	return b.String()
+}
+
+// Kinds of KeybindConflict
+const (
+	ConflictDuplicate = "duplicate" // the same keys are bound again
+	ConflictPrefix    = "prefix"    // one key sequence starts with the other
+	ConflictShadowed  = "shadowed"  // the keys replace a default binding
+)
+
+// KeybindConflict is a binding that stops another from working. Index is
+// the position of the binding in the list given to KeybindConflicts; the
+// binding it stops is the one at Other, or Default if Other is -1.
+type KeybindConflict struct {
+	Kind    string  `json:"kind"`
+	Index   int     `json:"index"`
+	Other   int     `json:"other"`
+	Default Keybind `json:"default"`
+}
+
+// KeybindConflicts finds the bindings of binds, in config order, that
+// replace an earlier one bound to the same keys or to keys that start or
+// continue its sequence, and those that replace one of defaults. Ghostty
+// keeps the later binding in both cases. "clear" removes the bindings
+// before it and the defaults, and an "unbind" of a default is no conflict.
+func KeybindConflicts(binds, defaults []Keybind) []KeybindConflict {
+	var conflicts []KeybindConflict
+	var active []int // bindings in effect, by index into binds
+	cleared := false
+	for i, kb := range binds {
+		if len(kb.Sequence) == 0 {
+			active, cleared = nil, true
+			continue
+		}
+		steps := kb.canonicalSteps()
+		active = slices.DeleteFunc(active, func(j int) bool {
+			kind := sequenceConflict(steps, binds[j].canonicalSteps())
+			if kind != "" {
+				conflicts = append(conflicts, KeybindConflict{Kind: kind, Index: i, Other: j})
+			}
+			return kind != ""
+		})
+		active = append(active, i)
+
+		if cleared || kb.Action == "unbind" {
+			continue
+		}
+		for _, d := range defaults {
+			kind := sequenceConflict(steps, d.canonicalSteps())
+			if kind == ConflictDuplicate {
+				if d.Action == kb.Action && d.Param == kb.Param {
+					continue // repeats the default
+				}
+				kind = ConflictShadowed
+			}
+			if kind != "" {
+				conflicts = append(conflicts, KeybindConflict{Kind: kind, Index: i, Other: -1, Default: d})
+			}
+		}
+	}
+	return conflicts
+}
+
+// canonicalSteps returns the key presses of the sequence in the form of
+// KeyTrigger.Canonical
+func (k Keybind) canonicalSteps() []string {
+	steps := make([]string, len(k.Sequence))
+	for i, t := range k.Sequence {
+		steps[i] = t.Canonical()
+	}
+	return steps
+}
+
+// sequenceConflict tells whether two key sequences are the same, whether
+// one starts with the other, or neither
+func sequenceConflict(a, b []string) string {
+	n := min(len(a), len(b))
+	switch {
+	case !slices.Equal(a[:n], b[:n]):
+		return ""
+	case len(a) == len(b):
+		return ConflictDuplicate
+	default:
+		return ConflictPrefix
+	}
+}
@@ -0,0 +1,61 @@
+package schema
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestKeybind(t *testing.T) {
+	for _, value := range []string{
+		"ctrl+shift+t=new_tab",
+		"global:unconsumed:ctrl+a>n=new_window",
+		"super+physical:bracket_left=goto_split:left",
+		"ctrl+==increase_font_size:1",
+		"ctrl+a=text:\\x15",
+		"clear",
+	} {
+		kb, err := ParseKeybind(value)
+		if err != nil {
+			t.Errorf("ParseKeybind(%q): %v", value, err)
+			continue
+		}
+		if kb.String() != value {
+			t.Errorf("Expected %q to round-trip, got %q", value, kb.String())
+		}
+	}
+
+	kb, _ := ParseKeybind("all:performable:ctrl+a>shift+n=goto_split:left")
+	if strings.Join(kb.Prefixes, ",") != "all,performable" || kb.Trigger() != "ctrl+a>shift+n" ||
+		len(kb.Sequence) != 2 || kb.Sequence[1].Key != "n" || kb.Action != "goto_split" || kb.Param != "left" {
+		t.Errorf("Unexpected keybind %+v", kb)
+	}
+
+	for _, value := range []string{"ctrl+a", "ctrl+a=", "ctrl+a+b=new_tab", "ctrl>=new_tab", "global:global:a=new_tab"} {
+		if _, err := ParseKeybind(value); err == nil {
+			t.Errorf("Expected ParseKeybind(%q) to fail", value)
+		}
+	}
+}
+
+func TestKeybindShadows(t *testing.T) {
+	defaults := []Keybind{}
+	for _, value := range []string{"super+shift+t=new_tab", "ctrl+shift+c=copy_to_clipboard", "ctrl+a>n=new_window"} {
+		kb, _ := ParseKeybind(value)
+		defaults = append(defaults, kb)
+	}
+
+	kb, _ := ParseKeybind("global:Shift+Cmd+T=new_window")
+	if kb.CanonicalTrigger() != "shift+super+t" {
+		t.Errorf("Expected canonical trigger shift+super+t, got %q", kb.CanonicalTrigger())
+	}
+	if shadowed := kb.Shadows(defaults); len(shadowed) != 1 || shadowed[0].Action != "new_tab" {
+		t.Errorf("Expected new_tab to be shadowed, got %+v", shadowed)
+	}
+
+	for _, value := range []string{"control+shift+c=copy_to_clipboard", "ctrl+a=new_window", "clear", "super+shift+t=unbind"} {
+		kb, _ := ParseKeybind(value)
+		if shadowed := kb.Shadows(defaults); len(shadowed) != 0 {
+			t.Errorf("Expected %q to shadow nothing, got %+v", value, shadowed)
+		}
+	}
+}
@@ -0,0 +1,113 @@
+package schema
+
+import (
+	_ "embed"
+	"encoding/json"
+	"regexp"
+	"runtime"
+	"slices"
+	"strings"
+)
+
+// Platforms an option can be limited to
+const (
+	PlatformMacOS = "macos"
+	PlatformGTK   = "gtk" // Linux and other systems using the GTK app
+)
+
+// Metadata is what is known about an option beyond its default and docs
+type Metadata struct {
+	Choices    []string `json:"choices,omitempty"`    // see Option.Choices
+	Repeatable bool     `json:"repeatable,omitempty"` // the key may appear more than once
+	Platforms  []string `json:"platforms,omitempty"`  // empty if it applies everywhere
+	Since      string   `json:"since,omitempty"`      // Ghostty version that added it
+	Deprecated string   `json:"deprecated,omitempty"` // why it should not be used
+}
+
+// overlay holds metadata the docs do not state, maintained by hand
+//
+//go:embed overlay.json
+var overlayJSON []byte
+
+var overlay = func() map[string]Metadata {
+	var m map[string]Metadata
+	if err := json.Unmarshal(overlayJSON, &m); err != nil {
+		panic("schema: invalid overlay.json: " + err.Error())
+	}
+	return m
+}()
+
+var (
+	// platformRe finds notes such as "Only supported on macOS" or "GTK only"
+	platformRe = regexp.MustCompile(`(?i)\bonly (?:supported|used|available|implemented|works) on (macos|linux|gtk)\b|\b(macos|linux|gtk)[- ]only\b`)
+	// sinceRe finds notes such as "Available since: 1.2.0"
+	sinceRe = regexp.MustCompile(`(?i)\bavailable since:? *v?(\d+\.\d+(?:\.\d+)?)`)
+	// deprecatedRe finds deprecation notes
+	deprecatedRe = regexp.MustCompile(`(?i)\b(?:is|are|has been) deprecated\b|^deprecated\b`)
+)
+
+// Metadata returns the metadata of an option, from overlay.json and,
+// where it says nothing, from the option's docs
+func (o Option) Metadata() Metadata {
+	meta := overlay[o.Key]
+	meta.Choices = o.Choices()
+	if meta.Platforms == nil {
+		meta.Platforms = docPlatforms(o)
+	}
+	if meta.Since == "" {
+		if m := sinceRe.FindStringSubmatch(o.Description); m != nil {
+			meta.Since = m[1]
+		}
+	}
+	if meta.Deprecated == "" {
+		meta.Deprecated = deprecation(o.Description)
+	}
+	return meta
+}
+
+// Supports reports whether the option applies on platform
+func (m Metadata) Supports(platform string) bool {
+	return len(m.Platforms) == 0 || slices.Contains(m.Platforms, platform)
+}
+
+// IsRepeatable reports whether a key may appear multiple times in a config
+func IsRepeatable(key string) bool {
+	return overlay[key].Repeatable
+}
+
+// CurrentPlatform returns the platform ghostconfig runs on
+func CurrentPlatform() string {
+	if runtime.GOOS == "darwin" {
+		return PlatformMacOS
+	}
+	return PlatformGTK
+}
+
+// docPlatforms infers platforms from the key prefix or the docs
+func docPlatforms(o Option) []string {
+	switch {
+	case strings.HasPrefix(o.Key, "macos-"):
+		return []string{PlatformMacOS}
+	case strings.HasPrefix(o.Key, "gtk-"), strings.HasPrefix(o.Key, "adw-"),
+		strings.HasPrefix(o.Key, "linux-"), strings.HasPrefix(o.Key, "x11-"):
+		return []string{PlatformGTK}
+	}
+	if m := platformRe.FindStringSubmatch(o.Description); m != nil {
+		if strings.EqualFold(m[1]+m[2], "macos") {
+			return []string{PlatformMacOS}
+		}
+		return []string{PlatformGTK}
+	}
+	return nil
+}
+
+// deprecation returns the paragraph of a description that says the
+// option is deprecated, on one line
+func deprecation(desc string) string {
+	for _, para := range strings.Split(desc, "\n\n") {
+		if deprecatedRe.MatchString(para) {
+			return strings.Join(strings.Fields(para), " ")
+		}
+	}
+	return ""
+}
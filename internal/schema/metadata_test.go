@@ -0,0 +1,47 @@
+package schema
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestMetadata(t *testing.T) {
+	options := loadDocs(t, "show-config-docs.txt")
+	if meta := options["window-save-state"].Metadata(); strings.Join(meta.Platforms, ",") != PlatformMacOS {
+		t.Errorf("Expected window-save-state to be limited to macOS, got %v", meta.Platforms)
+	}
+	if meta := options["cursor-style"].Metadata(); meta.Platforms != nil || meta.Deprecated != "" {
+		t.Errorf("Expected no platforms or deprecation for cursor-style, got %+v", meta)
+	}
+
+	for _, tc := range []struct {
+		opt        Option
+		platforms  string
+		since      string
+		deprecated string
+	}{
+		{opt: Option{Key: "window-vsync", Description: "Synchronize rendering. This is only supported on macOS."}, platforms: PlatformMacOS},
+		{opt: Option{Key: "gtk-titlebar"}, platforms: PlatformGTK},
+		{opt: Option{Key: "macos-icon"}, platforms: PlatformMacOS},
+		{opt: Option{Key: "some-option", Description: "Control something.\n\nAvailable since: 1.1.0"}, since: "1.1.0"},
+		{
+			opt:        Option{Key: "old-option", Description: "Does something.\n\nThis option is deprecated and\nhas no effect."},
+			deprecated: "This option is deprecated and has no effect.",
+		},
+	} {
+		meta := tc.opt.Metadata()
+		if got := strings.Join(meta.Platforms, ","); got != tc.platforms {
+			t.Errorf("%s: expected platforms %q, got %q", tc.opt.Key, tc.platforms, got)
+		}
+		if meta.Since != tc.since {
+			t.Errorf("%s: expected since %q, got %q", tc.opt.Key, tc.since, meta.Since)
+		}
+		if meta.Deprecated != tc.deprecated {
+			t.Errorf("%s: expected deprecation %q, got %q", tc.opt.Key, tc.deprecated, meta.Deprecated)
+		}
+	}
+
+	if !IsRepeatable("keybind") || IsRepeatable("font-size") {
+		t.Error("Expected keybind, and not font-size, to be repeatable")
+	}
+}
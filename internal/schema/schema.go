@@ -3,6 +3,7 @@ package schema
 import (
 	"bufio"
 	"os/exec"
+	"slices"
 	"strings"
 
 	"github.com/otiai10/ghostconfig/internal/i18n"
@@ -10,10 +11,10 @@ import (
 
 // Option represents a single Ghostty configuration option
 type Option struct {
-	Key          string
-	DefaultValue string
-	Description  string
-	Section      string
+	Key          string `json:"key"`
+	DefaultValue string `json:"default"`
+	Description  string `json:"description"`
+	Section      string `json:"section,omitempty"`
 }
 
 // Section represents a group of related options
@@ -50,6 +51,22 @@ var categoryOrder = []string{
 	CategoryAdvanced,
 }
 
+// Categories returns the categories in display order
+func Categories() []string {
+	return slices.Clone(categoryOrder)
+}
+
+// CategoryIndex returns the position of a category in the display order.
+// Unknown categories sort last.
+func CategoryIndex(category string) int {
+	for i, c := range categoryOrder {
+		if c == category {
+			return i
+		}
+	}
+	return len(categoryOrder)
+}
+
 // ExtractSection extracts the semantic category from a key
 func ExtractSection(key string) string {
 	// Font: font-*, adjust-*, grapheme-*, freetype-*, alpha-blending
@@ -171,6 +188,8 @@ func parseOutput(output string) []Option {
 	var currentValue string
 
 	scanner := bufio.NewScanner(strings.NewReader(output))
+	// Descriptions can hold lines longer than the default 64KB token limit
+	scanner.Buffer(nil, len(output)+1)
 	for scanner.Scan() {
 		line := scanner.Text()
 
@@ -0,0 +1,42 @@
+package schema
+
+import (
+	_ "embed"
+	"encoding/json"
+)
+
+//go:generate go run gen_snapshot.go
+
+// snapshot is the schema of a Ghostty release, used where Ghostty is not
+// installed. It is written by gen_snapshot.go in the cache format, with
+// the version line of the Ghostty it was generated from as the build.
+// A snapshot without a build was written by hand and only approximates
+// the release named by its version.
+//
+//go:embed snapshot.json
+var snapshot []byte
+
+// Embedded returns the schema snapshot built into ghostconfig
+func Embedded() (Schema, error) {
+	entry, err := readSnapshot(snapshot)
+	if err != nil {
+		return Schema{}, err
+	}
+	return Schema{Options: entry.Data, Version: entry.Version, Origin: OriginEmbedded}, nil
+}
+
+// release returns the embedded snapshot if it was generated from a Ghostty
+// release, so that its schema can stand for that version in the history
+func release() (Schema, bool) {
+	entry, err := readSnapshot(snapshot)
+	if err != nil || entry.Ghostty == "" || entry.Version == "" {
+		return Schema{}, false
+	}
+	return Schema{Options: entry.Data, Version: entry.Version, Origin: OriginEmbedded}, true
+}
+
+func readSnapshot(data []byte) (cacheEntry[[]Option], error) {
+	var entry cacheEntry[[]Option]
+	err := json.Unmarshal(data, &entry)
+	return entry, err
+}
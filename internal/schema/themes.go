@@ -0,0 +1,153 @@
+package schema
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"os"
+	"os/exec"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Theme is a Ghostty color theme with the colors it sets.
+// Colors are "#rrggbb" where the theme gives a hex color, as written
+// otherwise, and empty where the theme leaves the color unset.
+type Theme struct {
+	Name       string `json:"name"`
+	Source     string `json:"source"` // "resources" or "user"
+	Path       string `json:"path"`
+	Background string `json:"background"`
+	Foreground string `json:"foreground"`
+	Cursor     string `json:"cursor"`
+	// Palette holds the 16 ANSI colors
+	Palette []string `json:"palette"`
+}
+
+// listThemesRe matches a line of `ghostty +list-themes --path`,
+// "Name (resources) /path/to/Name". Names may hold spaces and parentheses.
+var listThemesRe = regexp.MustCompile(`^(.+?) \((resources|user)\) (.+)$`)
+
+// listThemes runs `ghostty +list-themes --path` and reads every theme file
+func listThemes() ([]Theme, error) {
+	output, err := exec.Command("ghostty", "+list-themes", "--path").Output()
+	if err != nil {
+		return nil, err
+	}
+	return parseThemeList(string(output)), nil
+}
+
+// parseThemeList reads the themes of `ghostty +list-themes --path` output.
+// A theme in the user's themes directory hides one of the same name that
+// ships with Ghostty, as in Ghostty.
+func parseThemeList(output string) []Theme {
+	var themes []Theme
+	index := make(map[string]int)
+	for _, line := range strings.Split(output, "\n") {
+		m := listThemesRe.FindStringSubmatch(strings.TrimSpace(line))
+		if m == nil {
+			continue
+		}
+		theme := Theme{Name: m[1], Source: m[2], Path: m[3]}
+		if data, err := os.ReadFile(theme.Path); err == nil {
+			theme.readColors(data)
+		}
+		if i, ok := index[theme.Name]; ok {
+			if theme.Source == "user" {
+				themes[i] = theme
+			}
+			continue
+		}
+		index[theme.Name] = len(themes)
+		themes = append(themes, theme)
+	}
+	return themes
+}
+
+// readColors reads the colors of a theme file, which uses the config syntax
+func (t *Theme) readColors(data []byte) {
+	t.Palette = make([]string, 16)
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		key, value, ok := strings.Cut(scanner.Text(), "=")
+		if !ok {
+			continue
+		}
+		key = strings.TrimSpace(key)
+		value = strings.Trim(strings.TrimSpace(value), `"`)
+		switch key {
+		case "background":
+			t.Background = hexColor(value)
+		case "foreground":
+			t.Foreground = hexColor(value)
+		case "cursor-color":
+			t.Cursor = hexColor(value)
+		case "palette":
+			index, color, ok := strings.Cut(value, "=")
+			if n, err := strconv.Atoi(strings.TrimSpace(index)); ok && err == nil && n >= 0 && n < len(t.Palette) {
+				t.Palette[n] = hexColor(strings.TrimSpace(color))
+			}
+		}
+	}
+}
+
+// hexColor prefixes hex colors written without "#" so browsers and
+// terminals take them; color names and invalid values are kept
+func hexColor(value string) string {
+	if (len(value) == 3 || len(value) == 6) && strings.Trim(value, "0123456789abcdefABCDEF") == "" {
+		return "#" + strings.ToLower(value)
+	}
+	return strings.ToLower(value)
+}
+
+// ThemeValue is a value of the theme option: one theme for both light
+// and dark mode, or "light:Name,dark:Name" to follow the system setting
+type ThemeValue struct {
+	Light  string
+	Dark   string
+	Paired bool // written as a light:/dark: pair
+	// DarkFirst keeps a pair written as "dark:Name,light:Name" in that
+	// order, so that reading and writing it back leaves the line alone
+	DarkFirst bool
+}
+
+// ParseThemeValue parses a value of the theme option. A pair must name
+// a theme for both modes; a single theme is used for both.
+func ParseThemeValue(value string) (ThemeValue, error) {
+	value = strings.TrimSpace(value)
+	if !strings.HasPrefix(value, "light:") && !strings.HasPrefix(value, "dark:") {
+		return ThemeValue{Light: value, Dark: value}, nil
+	}
+
+	v := ThemeValue{Paired: true, DarkFirst: strings.HasPrefix(value, "dark:")}
+	for _, part := range strings.Split(value, ",") {
+		mode, name, _ := strings.Cut(strings.TrimSpace(part), ":")
+		name = strings.TrimSpace(name)
+		switch {
+		case name == "":
+			return ThemeValue{}, fmt.Errorf("expected light:NAME,dark:NAME, got %q", value)
+		case mode == "light" && v.Light == "":
+			v.Light = name
+		case mode == "dark" && v.Dark == "":
+			v.Dark = name
+		default:
+			return ThemeValue{}, fmt.Errorf("expected light:NAME,dark:NAME, got %q", value)
+		}
+	}
+	if v.Light == "" || v.Dark == "" {
+		return ThemeValue{}, fmt.Errorf("a theme pair needs both light: and dark:, got %q", value)
+	}
+	return v, nil
+}
+
+// String returns the value as written in a config
+func (v ThemeValue) String() string {
+	switch {
+	case v.Paired && v.DarkFirst:
+		return "dark:" + v.Dark + ",light:" + v.Light
+	case v.Paired:
+		return "light:" + v.Light + ",dark:" + v.Dark
+	}
+	return v.Light
+}
@@ -0,0 +1,114 @@
+package schema
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestParseThemeList(t *testing.T) {
+	dir := t.TempDir()
+	resources := filepath.Join(dir, "Ghostty.app", "Contents", "Resources", "ghostty", "themes")
+	user := filepath.Join(dir, "config", "ghostty", "themes")
+	for _, d := range []string{resources, user} {
+		if err := os.MkdirAll(d, 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	write := func(path, content string) string {
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+		return path
+	}
+
+	dracula := write(filepath.Join(resources, "Dracula"), "background = 282a36\nforeground = f8f8f2\n")
+	userDracula := write(filepath.Join(user, "Dracula"), "background = 000000\n")
+	atom := write(filepath.Join(resources, "Atom One (Dark)"), "palette = 0=#000000\npalette = 15 = #FFFFFF\n")
+	solarized := write(filepath.Join(resources, "Builtin Solarized Light"), "cursor-color = \"#586e75\"\n")
+
+	output := strings.Join([]string{
+		"Atom One (Dark) (resources) " + atom,
+		"Builtin Solarized Light (resources) " + solarized,
+		"Dracula (resources) " + dracula,
+		"Dracula (user) " + userDracula,
+		"Missing (resources) " + filepath.Join(resources, "Missing"),
+		"not a theme line",
+		"",
+	}, "\n")
+
+	themes := parseThemeList(output)
+	var names []string
+	for _, th := range themes {
+		names = append(names, th.Name+" ("+th.Source+")")
+	}
+	expected := "Atom One (Dark) (resources), Builtin Solarized Light (resources), Dracula (user), Missing (resources)"
+	if got := strings.Join(names, ", "); got != expected {
+		t.Fatalf("Expected themes %s, got %s", expected, got)
+	}
+
+	if th := themes[0]; th.Path != atom || th.Palette[0] != "#000000" || th.Palette[15] != "#ffffff" {
+		t.Errorf("Unexpected Atom One (Dark) %+v", th)
+	}
+	if th := themes[1]; th.Cursor != "#586e75" {
+		t.Errorf("Expected the quoted cursor color, got %+v", th)
+	}
+	if th := themes[2]; th.Path != userDracula || th.Background != "#000000" || th.Foreground != "" {
+		t.Errorf("Expected the user's Dracula to replace Ghostty's, got %+v", th)
+	}
+	if th := themes[3]; th.Palette != nil || th.Background != "" {
+		t.Errorf("Expected no colors for a theme that cannot be read, got %+v", th)
+	}
+}
+
+func TestHexColor(t *testing.T) {
+	for value, expected := range map[string]string{
+		"282a36":  "#282a36",
+		"ABCDEF":  "#abcdef",
+		"#1E1E2E": "#1e1e2e",
+		"fff":     "#fff",
+		"#FFF":    "#fff",
+		"12345":   "12345", // neither 3 nor 6 digits
+		"#12":     "#12",
+		"#gggggg": "#gggggg",
+		"Red":     "red",
+		"bad":     "#bad", // three hex digits, as Ghostty reads it
+	} {
+		if got := hexColor(value); got != expected {
+			t.Errorf("hexColor(%q): expected %q, got %q", value, expected, got)
+		}
+	}
+}
+
+func TestReadColors(t *testing.T) {
+	var th Theme
+	th.readColors([]byte("# comment\nbackground=#12\nforeground = fg\npalette = 16=#ffffff\npalette = x=#000000\npalette = 3=abc\ncursor-color\n"))
+	if th.Background != "#12" || th.Foreground != "fg" || th.Cursor != "" {
+		t.Errorf("Expected invalid colors to be kept as written, got %+v", th)
+	}
+	if len(th.Palette) != 16 || th.Palette[3] != "#abc" || strings.Join(th.Palette, "") != "#abc" {
+		t.Errorf("Expected only palette 3 to be set, got %q", th.Palette)
+	}
+}
+
+func TestThemeValue(t *testing.T) {
+	for _, value := range []string{"Dracula", "Builtin Solarized Light", "light:Rose Pine Dawn,dark:Rose Pine", "light:Nord,dark:Nord", "dark:Dracula,light:Alucard"} {
+		v, err := ParseThemeValue(value)
+		if err != nil {
+			t.Errorf("ParseThemeValue(%q): %v", value, err)
+			continue
+		}
+		if v.String() != value {
+			t.Errorf("Expected %q to round-trip, got %q", value, v.String())
+		}
+	}
+	if v, _ := ParseThemeValue("dark:B, light:A"); v.Light != "A" || v.Dark != "B" || v.String() != "dark:B,light:A" {
+		t.Errorf("Expected light A and dark B, got %+v", v)
+	}
+	for _, value := range []string{"light:A", "dark:A,dark:B", "light:,dark:B", "light:A,dusk:B"} {
+		if _, err := ParseThemeValue(value); err == nil {
+			t.Errorf("Expected ParseThemeValue(%q) to fail", value)
+		}
+	}
+}
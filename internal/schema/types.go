@@ -1,7 +1,11 @@
 package schema
 
 import (
+	"fmt"
 	"os/exec"
+	"regexp"
+	"slices"
+	"strconv"
 	"strings"
 )
 
@@ -14,38 +18,303 @@ const (
 	TypeFont
 	TypeBool
 	TypeNumber
+	TypeInteger
+	TypeEnum
+	TypeDuration
+	TypePath
+	TypeTheme
+	TypeKeybind
 )
 
-// GetOptionType returns the type of a configuration option
-func GetOptionType(key string) OptionType {
-	// Color options
-	colorKeys := map[string]bool{
-		"background":               true,
-		"foreground":               true,
-		"bold-color":               true,
-		"cursor-color":             true,
-		"cursor-text":              true,
-		"selection-background":     true,
-		"selection-foreground":     true,
-		"split-divider-color":      true,
-		"window-padding-color":     true,
-		"window-titlebar-background": true,
-		"window-titlebar-foreground": true,
-	}
-	if colorKeys[key] || key == "palette" {
-		return TypeColor
-	}
+var typeNames = map[OptionType]string{
+	TypeText:     "text",
+	TypeColor:    "color",
+	TypeFont:     "font",
+	TypeBool:     "bool",
+	TypeNumber:   "number",
+	TypeInteger:  "integer",
+	TypeEnum:     "enum",
+	TypeDuration: "duration",
+	TypePath:     "path",
+	TypeTheme:    "theme",
+	TypeKeybind:  "keybind",
+}
+
+// String returns the name of the type as used by the GUI
+func (t OptionType) String() string {
+	return typeNames[t]
+}
+
+// Color options whose key does not end in "-color"
+var colorKeys = map[string]bool{
+	"background":                 true,
+	"foreground":                 true,
+	"cursor-text":                true,
+	"palette":                    true,
+	"selection-background":       true,
+	"selection-foreground":       true,
+	"unfocused-split-fill":       true,
+	"window-titlebar-background": true,
+	"window-titlebar-foreground": true,
+}
+
+// Path options whose key does not end in "-file" or "-path"
+var pathKeys = map[string]bool{
+	"config-file":       true,
+	"custom-shader":     true,
+	"gtk-custom-css":    true,
+	"working-directory": true,
+}
 
-	// Font options
-	if strings.HasPrefix(key, "font-family") {
+// Parts of keys of numeric options that take fractions
+var fractionalKeys = []string{"opacity", "contrast", "multiplier"}
+
+var (
+	// validValuesRe finds the sentence introducing a list of values
+	validValuesRe = regexp.MustCompile(`(?i)\b(valid|possible|available) values\b`)
+	// bulletRe matches a list item such as "  * `block` - a block cursor"
+	bulletRe = regexp.MustCompile(`^\s*[*-]\s+(.*)$`)
+	// literalRe matches a list item that is a literal value
+	literalRe = regexp.MustCompile("^`([^`]*)`")
+	// durationRe matches Ghostty durations such as "750ms" or "1h 30m"
+	durationRe = regexp.MustCompile(`^(\d+(\.\d+)?(ns|us|µs|ms|s|m|h|d|w|y) *)+$`)
+	// decimalRe finds decimal numbers in descriptions
+	decimalRe = regexp.MustCompile(`\d\.\d`)
+)
+
+// Type infers the type of an option from its key, its default value and
+// the "Valid values" list in its documentation
+func (o Option) Type() OptionType {
+	if strings.HasPrefix(o.Key, "font-family") {
 		return TypeFont
 	}
+	if o.Key == "theme" {
+		return TypeTheme
+	}
+	if o.Key == "keybind" {
+		return TypeKeybind
+	}
+	if choices, open := o.choices(); open {
+		return TypeText
+	} else if len(choices) > 0 {
+		if slices.Equal(choices, []string{"false", "true"}) || slices.Equal(choices, []string{"true", "false"}) {
+			return TypeBool
+		}
+		return TypeEnum
+	}
+	if colorKeys[o.Key] || strings.HasSuffix(o.Key, "-color") {
+		return TypeColor
+	}
+	if pathKeys[o.Key] || strings.HasSuffix(o.Key, "-file") || strings.HasSuffix(o.Key, "-path") {
+		return TypePath
+	}
 
+	value := o.DefaultValue
+	switch {
+	case value == "true" || value == "false":
+		return TypeBool
+	case durationRe.MatchString(value):
+		return TypeDuration
+	case value == "" && (strings.HasSuffix(o.Key, "-delay") || strings.HasSuffix(o.Key, "-timeout")):
+		return TypeDuration
+	}
+	if _, err := strconv.ParseInt(value, 10, 64); err == nil {
+		// Ghostty prints whole defaults of fractional options without a point
+		for _, hint := range fractionalKeys {
+			if strings.Contains(o.Key, hint) {
+				return TypeNumber
+			}
+		}
+		if strings.Contains(o.Description, "non-integer") || decimalRe.MatchString(o.Description) {
+			return TypeNumber
+		}
+		return TypeInteger
+	}
+	if _, err := strconv.ParseFloat(value, 64); err == nil {
+		return TypeNumber
+	}
 	return TypeText
 }
 
-// ListFonts returns available fonts from ghostty
-func ListFonts() ([]string, error) {
+// Choices returns the values listed as valid in the documentation of an
+// option, or nil if the documentation has no such list
+func (o Option) Choices() []string {
+	choices, open := o.choices()
+	if open {
+		return nil
+	}
+	return choices
+}
+
+// choices collects the literal values of the first "Valid values" list,
+// unless overlay.json lists them.
+// open is true if the list also describes values it cannot spell out,
+// such as "a positive integer".
+func (o Option) choices() (choices []string, open bool) {
+	if c := overlay[o.Key].Choices; c != nil {
+		return c, false
+	}
+	lines := strings.Split(o.Description, "\n")
+	for i, line := range lines {
+		if !validValuesRe.MatchString(line) {
+			continue
+		}
+		for _, l := range lines[i+1:] {
+			if m := bulletRe.FindStringSubmatch(l); m != nil {
+				if lit := literalRe.FindStringSubmatch(m[1]); lit != nil {
+					if v := strings.TrimSpace(lit[1]); v != "" {
+						choices = append(choices, v)
+					}
+				} else {
+					open = true
+				}
+				continue
+			}
+			// Blank lines and wrapped item text continue the list
+			if strings.TrimSpace(l) == "" || (len(choices) > 0 && strings.HasPrefix(l, " ")) {
+				continue
+			}
+			break
+		}
+		if len(choices) > 0 || open {
+			return choices, open
+		}
+	}
+	return nil, false
+}
+
+// CheckValue reports values that cannot be right for an option, judged
+// by its type. It is a quick offline check; Validate asks Ghostty itself.
+// An empty value resets an option to its default and is always accepted.
+func CheckValue(opt Option, value string) error {
+	if value == "" {
+		return nil
+	}
+	switch t := opt.Type(); t {
+	case TypeBool:
+		// Also the other spellings Ghostty accepts, such as 1 and f
+		if v := normalizeValue(opt, value); v != "true" && v != "false" {
+			return fmt.Errorf("expected true or false, got %q", value)
+		}
+	case TypeEnum:
+		if choices := opt.Choices(); !slices.Contains(choices, value) {
+			return fmt.Errorf("expected one of %s, got %q", strings.Join(choices, ", "), value)
+		}
+	case TypeNumber, TypeInteger:
+		// Some take a pair, such as window-padding-x = 2,4
+		for _, part := range strings.Split(value, ",") {
+			part = strings.TrimSpace(part)
+			if _, err := strconv.ParseFloat(part, 64); err != nil {
+				return fmt.Errorf("expected a number, got %q", value)
+			}
+			if _, err := strconv.ParseInt(part, 10, 64); err != nil && t == TypeInteger {
+				return fmt.Errorf("expected a whole number, got %q", value)
+			}
+		}
+	case TypeTheme:
+		if _, err := ParseThemeValue(value); err != nil {
+			return err
+		}
+	case TypeKeybind:
+		if _, err := ParseKeybind(value); err != nil {
+			return err
+		}
+	case TypeDuration:
+		if !durationRe.MatchString(value) {
+			return fmt.Errorf("expected a duration such as 750ms or 1h30m, got %q", value)
+		}
+	case TypeColor:
+		if opt.Key == "palette" {
+			// palette = N=COLOR
+			index, color, ok := strings.Cut(value, "=")
+			if n, err := strconv.Atoi(strings.TrimSpace(index)); !ok || err != nil || n < 0 || n > 255 {
+				return fmt.Errorf("expected INDEX=COLOR with an index from 0 to 255, got %q", value)
+			}
+			value = strings.TrimSpace(color)
+		}
+		// Some take a list, such as macos-icon-screen-color
+		for _, part := range strings.Split(value, ",") {
+			if !isColor(strings.TrimSpace(part)) {
+				return fmt.Errorf("expected a color such as #1e1e2e or a color name, got %q", value)
+			}
+		}
+	}
+	return nil
+}
+
+// SameValue reports whether two values of an option mean the same to
+// Ghostty, such as the colors #282C34 and 282c34, the booleans t and
+// true, or the numbers 13 and 13.0
+func SameValue(opt Option, a, b string) bool {
+	return normalizeValue(opt, a) == normalizeValue(opt, b)
+}
+
+// normalizeValue returns the canonical spelling of a value of opt
+func normalizeValue(opt Option, value string) string {
+	value = strings.TrimSpace(value)
+	switch opt.Type() {
+	case TypeBool:
+		// The spellings Ghostty's parser accepts
+		switch value {
+		case "1", "t", "T", "true":
+			return "true"
+		case "0", "f", "F", "false":
+			return "false"
+		}
+	case TypeNumber, TypeInteger:
+		parts := strings.Split(value, ",")
+		for i, part := range parts {
+			part = strings.TrimSpace(part)
+			if f, err := strconv.ParseFloat(part, 64); err == nil {
+				part = strconv.FormatFloat(f, 'g', -1, 64)
+			}
+			parts[i] = part
+		}
+		return strings.Join(parts, ",")
+	case TypeColor:
+		if opt.Key == "palette" {
+			if index, color, ok := strings.Cut(value, "="); ok {
+				if n, err := strconv.Atoi(strings.TrimSpace(index)); err == nil {
+					return strconv.Itoa(n) + "=" + normalizeColor(strings.TrimSpace(color))
+				}
+			}
+			return value
+		}
+		parts := strings.Split(value, ",")
+		for i, part := range parts {
+			parts[i] = normalizeColor(strings.TrimSpace(part))
+		}
+		return strings.Join(parts, ",")
+	}
+	return value
+}
+
+// normalizeColor writes hex colors as lowercase #rrggbb; names are kept
+func normalizeColor(value string) string {
+	hex := strings.TrimPrefix(value, "#")
+	if (len(hex) != 3 && len(hex) != 6) || strings.Trim(hex, "0123456789abcdefABCDEF") != "" {
+		return value
+	}
+	hex = strings.ToLower(hex)
+	if len(hex) == 3 {
+		hex = string([]byte{hex[0], hex[0], hex[1], hex[1], hex[2], hex[2]})
+	}
+	return "#" + hex
+}
+
+// isColor accepts hex colors with or without "#", X11 color names and
+// the special values of some options, such as cell-foreground
+func isColor(value string) bool {
+	hex := strings.TrimPrefix(value, "#")
+	isHex := (len(hex) == 3 || len(hex) == 6) && strings.Trim(hex, "0123456789abcdefABCDEF") == ""
+	if strings.HasPrefix(value, "#") {
+		return isHex
+	}
+	return isHex || strings.Trim(value, "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789 -") == ""
+}
+
+// listFonts returns available fonts from ghostty
+func listFonts() ([]string, error) {
 	cmd := exec.Command("ghostty", "+list-fonts")
 	output, err := cmd.Output()
 	if err != nil {
@@ -0,0 +1,127 @@
+package schema
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// loadDocs parses testdata in the format of `ghostty +show-config --default --docs`
+func loadDocs(t *testing.T, name string) map[string]Option {
+	t.Helper()
+	data, err := os.ReadFile(filepath.Join("testdata", name))
+	if err != nil {
+		t.Fatal(err)
+	}
+	options := make(map[string]Option)
+	for _, opt := range parseOutput(string(data)) {
+		options[opt.Key] = opt
+	}
+	return options
+}
+
+func TestOptionType(t *testing.T) {
+	options := loadDocs(t, "show-config-docs.txt")
+	for key, expected := range map[string]OptionType{
+		"font-size":               TypeNumber, // "can be a non-integer"
+		"cursor-style":            TypeEnum,
+		"mouse-hide-while-typing": TypeBool,
+		"background-blur":         TypeText, // also takes an intensity
+		"background-opacity":      TypeNumber,
+		"scrollback-limit":        TypeInteger,
+		"resize-overlay-duration": TypeDuration,
+		"window-save-state":       TypeEnum,
+	} {
+		opt, ok := options[key]
+		if !ok {
+			t.Errorf("%s was not parsed", key)
+			continue
+		}
+		if got := opt.Type(); got != expected {
+			t.Errorf("%s: expected type %s, got %s", key, expected, got)
+		}
+	}
+}
+
+func TestOptionChoices(t *testing.T) {
+	options := loadDocs(t, "show-config-docs.txt")
+	for key, expected := range map[string]string{
+		"cursor-style":      "block,bar,underline,block_hollow",
+		"window-save-state": "default,never,always",
+		"background-blur":   "",
+		"font-size":         "",
+	} {
+		if got := strings.Join(options[key].Choices(), ","); got != expected {
+			t.Errorf("%s: expected choices %q, got %q", key, expected, got)
+		}
+	}
+
+	// Item text wrapped onto the next line does not end the list
+	opt := Option{Key: "cursor-style", Description: "The style of the cursor.\n\nValid values are:\n\n  * `block`\n  * `bar`\n    A vertical bar.\n  * `underline`\n\nShell integration changes it."}
+	if choices := opt.Choices(); strings.Join(choices, ",") != "block,bar,underline" {
+		t.Errorf("Expected choices block,bar,underline, got %v", choices)
+	}
+}
+
+func TestSameValue(t *testing.T) {
+	options := loadDocs(t, "show-config-docs.txt")
+	background := Option{Key: "background", DefaultValue: "#282c34"}
+	palette := Option{Key: "palette"}
+	tests := []struct {
+		opt  Option
+		a, b string
+		same bool
+	}{
+		{background, "#282c34", "282c34", true},
+		{background, "#282C34", "282c34", true},
+		{background, "#fff", "ffffff", true},
+		{background, "#282c34", "#282c35", false},
+		{background, "black", "black", true},
+		{palette, "0=#1D1F21", "0 = 1d1f21", true},
+		{palette, "0=#1d1f21", "1=#1d1f21", false},
+		{options["mouse-hide-while-typing"], "false", "f", true},
+		{options["mouse-hide-while-typing"], "true", "1", true},
+		{options["mouse-hide-while-typing"], "true", "false", false},
+		{options["font-size"], "13", "13.0", true},
+		{options["font-size"], "13", "13.5", false},
+		{options["cursor-style"], "block", " block", true},
+		{options["cursor-style"], "block", "Block", false},
+	}
+	for _, tt := range tests {
+		if got := SameValue(tt.opt, tt.a, tt.b); got != tt.same {
+			t.Errorf("SameValue(%s, %q, %q) = %v, expected %v", tt.opt.Key, tt.a, tt.b, got, tt.same)
+		}
+	}
+}
+
+func TestCheckValue(t *testing.T) {
+	options := loadDocs(t, "show-config-docs.txt")
+	tests := []struct {
+		opt   Option
+		value string
+		ok    bool
+	}{
+		{options["mouse-hide-while-typing"], "true", true},
+		{options["mouse-hide-while-typing"], "false", true},
+		{options["mouse-hide-while-typing"], "1", true},
+		{options["mouse-hide-while-typing"], "t", true},
+		{options["mouse-hide-while-typing"], "T", true},
+		{options["mouse-hide-while-typing"], "0", true},
+		{options["mouse-hide-while-typing"], "f", true},
+		{options["mouse-hide-while-typing"], "F", true},
+		{options["mouse-hide-while-typing"], "yes", false},
+		{options["mouse-hide-while-typing"], "True", false},
+		{Option{Key: "cursor-color"}, "cell-foreground", true},
+		{Option{Key: "selection-background"}, "cell-background", true},
+		{Option{Key: "background"}, "#282c34", true},
+		{Option{Key: "background"}, "dark slate gray", true},
+		{Option{Key: "background"}, "#12", false},
+		{Option{Key: "background"}, "rgb(0,0,0)", false},
+	}
+	for _, tt := range tests {
+		if err := CheckValue(tt.opt, tt.value); (err == nil) != tt.ok {
+			t.Errorf("CheckValue(%s, %q) = %v, expected ok=%v", tt.opt.Key, tt.value, err, tt.ok)
+		}
+	}
+}
@@ -0,0 +1,92 @@
+package schema
+
+import (
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// ValidationError is a problem Ghostty reported for a config.
+// Line is 1-based and 0 when Ghostty did not name one; Key may be empty.
+type ValidationError struct {
+	Line    int    `json:"line"`
+	Key     string `json:"key"`
+	Message string `json:"message"`
+}
+
+func (e ValidationError) Error() string {
+	if e.Key == "" {
+		return e.Message
+	}
+	return e.Key + ": " + e.Message
+}
+
+// ghostty prints problems as "path:line:key: message", "path:line: message"
+// or "key: message"
+var (
+	validateLineRe = regexp.MustCompile(`^(.*):(\d+):(?:([a-z0-9-]+):)?\s*(.*)$`)
+	validateKeyRe  = regexp.MustCompile(`^([a-z0-9-]+):\s*(.*)$`)
+)
+
+// Validate runs `ghostty +validate-config` on config file contents and
+// returns the problems it reports, or nil if Ghostty accepts the config.
+// An error means Ghostty could not be run at all.
+func Validate(content []byte) ([]ValidationError, error) {
+	f, err := os.CreateTemp("", "ghostconfig-validate-*")
+	if err != nil {
+		return nil, err
+	}
+	defer os.Remove(f.Name())
+	_, err = f.Write(content)
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	cmd := exec.Command("ghostty", "+validate-config", "--config-file="+f.Name())
+	output, err := cmd.CombinedOutput()
+	var exitErr *exec.ExitError
+	if err == nil {
+		return nil, nil
+	}
+	if !errors.As(err, &exitErr) {
+		return nil, err
+	}
+
+	problems := parseValidateOutput(string(output), f.Name())
+	if len(problems) == 0 {
+		// Failed without saying where
+		problems = append(problems, ValidationError{Message: strings.TrimSpace(string(output))})
+	}
+	return problems, nil
+}
+
+// parseValidateOutput reads the problems ghostty printed for the config
+// at path. Ghostty may print path with its symlinks resolved, such as
+// /private/var for /var on macOS, so only the file name has to match.
+func parseValidateOutput(output, path string) []ValidationError {
+	var problems []ValidationError
+	for _, line := range strings.Split(output, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+		if m := validateLineRe.FindStringSubmatch(line); m != nil && filepath.Base(m[1]) == filepath.Base(path) {
+			n, _ := strconv.Atoi(m[2])
+			problems = append(problems, ValidationError{Line: n, Key: m[3], Message: m[4]})
+			continue
+		}
+		if m := validateKeyRe.FindStringSubmatch(line); m != nil {
+			problems = append(problems, ValidationError{Key: m[1], Message: m[2]})
+			continue
+		}
+		problems = append(problems, ValidationError{Message: line})
+	}
+	return problems
+}
@@ -0,0 +1,48 @@
+package schema
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestParseValidateOutput(t *testing.T) {
+	// Lines in the form Ghostty's cli diagnostics print them,
+	// "path:line:key: message", "path:line: message" and "key: message"
+	output, err := os.ReadFile(filepath.Join("testdata", "validate-config.txt"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []ValidationError{
+		{Line: 3, Key: "font-size", Message: `invalid value "abc"`},
+		{Line: 5, Key: "font-familly", Message: "unknown field"},
+		{Line: 7, Message: `missing "=" in line`},
+		{Key: "cursor-style", Message: `invalid value "triangle"`},
+		{Message: "config error without a key"},
+	}
+	got := parseValidateOutput(string(output), "/tmp/ghostconfig-validate-1234")
+	if len(got) != len(expected) {
+		t.Fatalf("Expected %d problems, got %+v", len(expected), got)
+	}
+	for i := range expected {
+		if got[i] != expected[i] {
+			t.Errorf("Line %d: expected %+v, got %+v", i+1, expected[i], got[i])
+		}
+	}
+}
+
+func TestParseValidateOutputResolvedPath(t *testing.T) {
+	// macOS temp files live under /var, which ghostty prints resolved
+	got := parseValidateOutput("/private/var/folders/x/T/ghostconfig-validate-99:2:theme: theme \"Nope\" not found\n",
+		"/var/folders/x/T/ghostconfig-validate-99")
+	if len(got) != 1 || got[0].Line != 2 || got[0].Key != "theme" || got[0].Message != `theme "Nope" not found` {
+		t.Errorf("Unexpected problems %+v", got)
+	}
+
+	// Paths of other files are not taken for line numbers of this one
+	got = parseValidateOutput("/home/me/other:4:font-size: invalid value\n", "/tmp/ghostconfig-validate-1")
+	if len(got) != 1 || got[0].Line != 0 {
+		t.Errorf("Expected a problem without a line, got %+v", got)
+	}
+}
@@ -1,8 +1,12 @@
 package tui
 
 import (
+	"cmp"
+	"errors"
 	"fmt"
+	"slices"
 	"strings"
+	"time"
 
 	"github.com/charmbracelet/bubbles/textinput"
 	"github.com/charmbracelet/bubbletea"
@@ -19,7 +23,11 @@ const (
 	modeEdit
 	modeSearch
 	modeColorPicker
+	modeThemePicker
 	modeFontPicker
+	modeChoicePicker
+	modeValues
+	modeKeybindEdit
 )
 
 // ListItem represents either a section header or an option
@@ -34,7 +42,9 @@ type Model struct {
 	config      *config.Config
 	cursor      int
 	offset      int
-	height      int
+	height      int // rows of the list, see layout
+	width       int // of the terminal, 0 until known
+	winHeight   int // of the terminal, 0 until known
 	mode        mode
 	textInput   textinput.Model
 	searchQuery string
@@ -45,11 +55,48 @@ type Model struct {
 	colorCursor int
 	customColor bool
 
+	// For theme picker; themeSlot is "light" or "dark" for a pair
+	themes      []schema.Theme
+	themeCursor int
+	themeOffset int
+	themeFilter string
+	themeValue  schema.ThemeValue
+	themeSlot   string
+
 	// For font picker
-	fonts       []string
-	fontCursor  int
-	fontOffset  int
-	fontFilter  string
+	fonts      []string
+	fontCursor int
+	fontOffset int
+	fontFilter string
+
+	// For bool and enum options
+	choices      []string
+	choiceCursor int
+
+	// For repeatable keys: the value being edited (-1 replaces all values)
+	// and the mode to return to after an edit
+	valueCursor int
+	valueIndex  int
+	returnMode  mode
+
+	// For keybinds: the trigger with its prefixes, and the action with its
+	// parameter, edited as two fields
+	keybindInputs [2]textinput.Model
+	keybindField  int
+
+	// Actions offered in the action field and the bindings Ghostty has by
+	// default; both are empty if ghostty cannot list them
+	actions         []schema.Action
+	defaultKeybinds []schema.Keybind
+
+	// For conflicts with changes made on disk
+	conflictCursor int
+
+	// Problems found in the config files; problemIndex is the one shown
+	options      []schema.Option
+	schema       schema.Schema
+	problems     []config.Diagnostic
+	problemIndex int
 }
 
 var (
@@ -113,7 +160,21 @@ var (
 			Foreground(lipgloss.Color("252"))
 )
 
-func New(options []schema.Option, cfg *config.Config) Model {
+// problemStyle colors a diagnostic by severity
+func problemStyle(severity config.Severity) lipgloss.Style {
+	switch severity {
+	case config.SeverityError:
+		return lipgloss.NewStyle().Foreground(lipgloss.Color("203"))
+	case config.SeverityWarning:
+		return lipgloss.NewStyle().Foreground(lipgloss.Color("221"))
+	default:
+		return lipgloss.NewStyle().Foreground(lipgloss.Color("117"))
+	}
+}
+
+func New(sch schema.Schema, cfg *config.Config) Model {
+	options := sch.Options
+
 	ti := textinput.New()
 	ti.Placeholder = i18n.T("tui.placeholder")
 	ti.CharLimit = 256
@@ -121,11 +182,31 @@ func New(options []schema.Option, cfg *config.Config) Model {
 
 	sections := schema.GroupBySection(options)
 
+	var keybindInputs [2]textinput.Model
+	for i, placeholder := range []string{"ctrl+a>n", "new_tab"} {
+		keybindInputs[i] = textinput.New()
+		keybindInputs[i].Placeholder = placeholder
+		keybindInputs[i].CharLimit = 256
+		keybindInputs[i].Width = 40
+	}
+
+	// Keybinds are checked against Ghostty's defaults when it can list them
+	defaultKeybinds, _ := schema.ListKeybinds()
+
 	m := Model{
-		sections:  sections,
-		config:    cfg,
-		height:    20,
-		textInput: ti,
+		sections:        sections,
+		config:          cfg,
+		height:          20,
+		textInput:       ti,
+		keybindInputs:   keybindInputs,
+		defaultKeybinds: defaultKeybinds,
+		options:         options,
+		schema:          sch,
+		problems:        cfg.Diagnose(options, defaultKeybinds),
+	}
+
+	if len(cfg.IncludeErrors) > 0 {
+		m.message = fmt.Sprintf(i18n.T("msg.error"), cfg.IncludeErrors[0])
 	}
 
 	m.rebuildItems()
@@ -167,13 +248,37 @@ func (m *Model) rebuildItems() {
 	}
 }
 
+// watchInterval is how often the config files are checked for changes
+// made by other programs
+const watchInterval = 2 * time.Second
+
+// watchMsg asks to check the config files for changes made on disk
+type watchMsg struct{}
+
+func watch() tea.Cmd {
+	return tea.Tick(watchInterval, func(time.Time) tea.Msg { return watchMsg{} })
+}
+
 func (m Model) Init() tea.Cmd {
-	return nil
+	return watch()
 }
 
 func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	model, cmd := m.update(msg)
+	if m, ok := model.(Model); ok {
+		m.layout()
+		return m, cmd
+	}
+	return model, cmd
+}
+
+func (m Model) update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	switch msg := msg.(type) {
 	case tea.KeyMsg:
+		// Conflicts must be resolved before anything else is edited
+		if len(m.config.Conflicts()) > 0 {
+			return m.updateConflicts(msg)
+		}
 		switch m.mode {
 		case modeList:
 			return m.updateList(msg)
@@ -183,20 +288,54 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			return m.updateSearch(msg)
 		case modeColorPicker:
 			return m.updateColorPicker(msg)
+		case modeThemePicker:
+			return m.updateThemePicker(msg)
 		case modeFontPicker:
 			return m.updateFontPicker(msg)
+		case modeChoicePicker:
+			return m.updateChoicePicker(msg)
+		case modeValues:
+			return m.updateValues(msg)
+		case modeKeybindEdit:
+			return m.updateKeybindEdit(msg)
 		}
 
-	case tea.WindowSizeMsg:
-		m.height = msg.Height - 10
-		if m.height < 5 {
-			m.height = 5
+	case watchMsg:
+		// Saving merges the changes, or lists the conflicts they cause
+		if files, err := m.config.ExternalChanges(); err == nil && len(files) > 0 {
+			m.save(fmt.Sprintf(i18n.T("msg.external_changes"), strings.Join(files, ", ")))
 		}
+		return m, watch()
+
+	case tea.WindowSizeMsg:
+		m.width, m.winHeight = msg.Width, msg.Height
 	}
 
 	return m, nil
 }
 
+// layout gives the list the lines of the terminal that the header and
+// footer of the list view leave, as they are rendered for the current
+// cursor, and scrolls the list so that the cursor stays visible. The
+// pickers keep the height of the list they were opened from.
+func (m *Model) layout() {
+	if m.winHeight == 0 || (m.mode != modeList && m.mode != modeSearch && m.mode != modeEdit) {
+		return
+	}
+	chrome := m.viewHeader() + m.viewFooter()
+	if m.width > 0 {
+		// Long lines wrap in the terminal
+		chrome = lipgloss.NewStyle().Width(m.width).Render(chrome)
+	}
+	m.height = max(m.winHeight-lipgloss.Height(chrome), 3)
+	if m.cursor >= m.offset+m.height {
+		m.offset = m.cursor - m.height + 1
+	}
+	if m.cursor < m.offset {
+		m.offset = m.cursor
+	}
+}
+
 func (m Model) updateList(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	switch msg.String() {
 	case "q", "ctrl+c":
@@ -229,7 +368,18 @@ func (m Model) updateList(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 				}
 			} else {
 				opt := m.sections[item.SectionIndex].Options[item.OptionIndex]
-				optType := schema.GetOptionType(opt.Key)
+				optType := opt.Type()
+				m.valueIndex = -1
+				m.returnMode = modeList
+
+				if config.IsRepeatable(opt.Key) {
+					m.mode = modeValues
+					m.valueCursor = 0
+					if optType == schema.TypeKeybind {
+						m.loadActions()
+					}
+					return m, nil
+				}
 
 				switch optType {
 				case schema.TypeColor:
@@ -244,28 +394,13 @@ func (m Model) updateList(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 					return m, nil
 
 				case schema.TypeFont:
-					fonts, err := schema.ListFonts()
-					if err != nil {
-						m.message = fmt.Sprintf(i18n.T("msg.loading_fonts"), err)
-						return m, nil
-					}
-					m.fonts = fonts
-					m.mode = modeFontPicker
-					m.fontCursor = 0
-					m.fontOffset = 0
-					m.fontFilter = ""
-					// Find current font in list
-					currentVal := m.config.Get(opt.Key)
-					for i, f := range fonts {
-						if f == currentVal {
-							m.fontCursor = i
-							if m.fontCursor >= m.height-2 {
-								m.fontOffset = m.fontCursor - m.height/2
-							}
-							break
-						}
-					}
-					return m, nil
+					return m.openFontPicker(m.config.Get(opt.Key))
+
+				case schema.TypeTheme:
+					return m.openThemePicker(m.config.Get(opt.Key))
+
+				case schema.TypeBool, schema.TypeEnum:
+					return m.openChoicePicker(opt, m.config.Get(opt.Key))
 
 				default:
 					m.mode = modeEdit
@@ -293,6 +428,28 @@ func (m Model) updateList(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		}
 		m.rebuildItems()
 
+	case "p":
+		// Cycle through the problems shown in the status area
+		if len(m.problems) > 0 {
+			m.problemIndex = (m.problemIndex + 1) % len(m.problems)
+		}
+
+	case "x", "delete":
+		if len(m.items) > 0 && m.cursor < len(m.items) && !m.items[m.cursor].IsSection {
+			opt := m.currentOption()
+			if len(m.config.GetAll(opt.Key)) == 0 {
+				return m, nil
+			}
+			m.config.Unset(opt.Key)
+			m.save(fmt.Sprintf(i18n.T("msg.reset"), opt.Key))
+		}
+
+	case "u":
+		m.undo()
+
+	case "ctrl+r":
+		m.redo()
+
 	case "/":
 		m.mode = modeSearch
 		m.textInput.SetValue(m.searchQuery)
@@ -312,24 +469,122 @@ func (m Model) updateList(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	return m, nil
 }
 
+func (m Model) openFontPicker(currentVal string) (tea.Model, tea.Cmd) {
+	fonts, err := schema.ListFonts()
+	if err != nil {
+		m.message = fmt.Sprintf(i18n.T("msg.loading_fonts"), err)
+		return m, nil
+	}
+	m.fonts = fonts
+	m.mode = modeFontPicker
+	m.fontCursor = 0
+	m.fontOffset = 0
+	m.fontFilter = ""
+	// Find current font in list
+	for i, f := range fonts {
+		if f == currentVal {
+			m.fontCursor = i
+			if m.fontCursor >= m.height-2 {
+				m.fontOffset = m.fontCursor - m.height/2
+			}
+			break
+		}
+	}
+	return m, nil
+}
+
+func (m Model) openThemePicker(currentVal string) (tea.Model, tea.Cmd) {
+	themes, err := schema.ListThemes()
+	if err != nil {
+		m.message = fmt.Sprintf(i18n.T("msg.loading_themes"), err)
+		return m, nil
+	}
+	m.themes = themes
+	m.mode = modeThemePicker
+	m.themeFilter = ""
+	if m.themeValue, err = schema.ParseThemeValue(currentVal); err != nil {
+		m.themeValue = schema.ThemeValue{Light: currentVal, Dark: currentVal}
+	}
+	m.selectThemeSlot("light")
+	return m, nil
+}
+
+// selectThemeSlot makes slot the one the theme picker fills and moves
+// the cursor to its theme
+func (m *Model) selectThemeSlot(slot string) {
+	m.themeSlot = slot
+	name := m.themeValue.Light
+	if slot == "dark" {
+		name = m.themeValue.Dark
+	}
+	m.themeCursor = max(slices.IndexFunc(m.getFilteredThemes(), func(t schema.Theme) bool { return t.Name == name }), 0)
+	m.themeOffset = max(m.themeCursor-m.themePickerHeight()/2, 0)
+}
+
+// themePickerHeight is the number of themes listed above the preview
+func (m Model) themePickerHeight() int {
+	return max(m.height-8, 3)
+}
+
+// openChoicePicker lists the values of a bool or enum option
+func (m Model) openChoicePicker(opt schema.Option, currentVal string) (tea.Model, tea.Cmd) {
+	m.choices = opt.Choices()
+	if opt.Type() == schema.TypeBool {
+		m.choices = []string{"true", "false"}
+	}
+	if currentVal == "" {
+		currentVal = opt.DefaultValue
+	}
+	m.mode = modeChoicePicker
+	m.choiceCursor = max(slices.Index(m.choices, currentVal), 0)
+	return m, nil
+}
+
+// currentOption returns the option under the list cursor
+func (m Model) currentOption() schema.Option {
+	item := m.items[m.cursor]
+	return m.sections[item.SectionIndex].Options[item.OptionIndex]
+}
+
+// commitValue stores a value for the option being edited and saves the config.
+// Depending on valueIndex it replaces all values, one value, or appends a new one.
+func (m *Model) commitValue(key, value string) {
+	var err error
+	depth := m.config.UndoDepth()
+	switch {
+	case m.valueIndex < 0:
+		err = m.config.Set(key, value)
+	case m.valueIndex >= len(m.config.GetAll(key)):
+		err = m.config.Add(key, value)
+	default:
+		err = m.config.SetAt(key, m.valueIndex, value)
+	}
+	if err != nil {
+		m.message = fmt.Sprintf(i18n.T("msg.error"), err)
+		return
+	}
+	if !m.valid(key, depth) {
+		return
+	}
+	m.save(fmt.Sprintf(i18n.T("msg.saved"), key, value))
+}
+
 func (m Model) updateEdit(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	switch msg.String() {
 	case "esc":
-		m.mode = modeList
+		m.mode = m.returnMode
 		m.textInput.Blur()
 		return m, nil
 
 	case "enter":
-		item := m.items[m.cursor]
-		opt := m.sections[item.SectionIndex].Options[item.OptionIndex]
-		newValue := m.textInput.Value()
-		m.config.Set(opt.Key, newValue)
-		if err := m.config.Save(); err != nil {
-			m.message = fmt.Sprintf(i18n.T("msg.error"), err)
-		} else {
-			m.message = fmt.Sprintf(i18n.T("msg.saved"), opt.Key, newValue)
+		opt := m.currentOption()
+		// Keep editing values that cannot be right for the option's type
+		if err := schema.CheckValue(opt, m.textInput.Value()); err != nil {
+			m.message = fmt.Sprintf(i18n.T("msg.bad_value"), err)
+			return m, nil
 		}
-		m.mode = modeList
+		m.commitValue(opt.Key, m.textInput.Value())
+		m.mode = m.returnMode
 		m.textInput.Blur()
 		return m, nil
 	}
@@ -367,7 +622,7 @@ func (m Model) updateColorPicker(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 
 	switch msg.String() {
 	case "esc":
-		m.mode = modeList
+		m.mode = m.returnMode
 		return m, nil
 
 	case "up", "k":
@@ -397,13 +652,8 @@ func (m Model) updateColorPicker(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 			newValue = schema.CommonColors[m.colorCursor].Value
 		}
 
-		m.config.Set(opt.Key, newValue)
-		if err := m.config.Save(); err != nil {
-			m.message = fmt.Sprintf(i18n.T("msg.error"), err)
-		} else {
-			m.message = fmt.Sprintf(i18n.T("msg.saved"), opt.Key, newValue)
-		}
-		m.mode = modeList
+		m.commitValue(opt.Key, newValue)
+		m.mode = m.returnMode
 		m.textInput.Blur()
 		return m, nil
 	}
@@ -417,13 +667,100 @@ func (m Model) updateColorPicker(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	return m, nil
 }
 
+func (m Model) updateThemePicker(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	filtered := m.getFilteredThemes()
+	maxVisible := m.themePickerHeight()
+
+	switch msg.String() {
+	case "esc":
+		m.mode = m.returnMode
+		m.themeFilter = ""
+		return m, nil
+
+	case "up", "k":
+		if m.themeCursor > 0 {
+			m.themeCursor--
+			if m.themeCursor < m.themeOffset {
+				m.themeOffset = m.themeCursor
+			}
+		}
+
+	case "down", "j":
+		if m.themeCursor < len(filtered)-1 {
+			m.themeCursor++
+			if m.themeCursor >= m.themeOffset+maxVisible {
+				m.themeOffset = m.themeCursor - maxVisible + 1
+			}
+		}
+
+	case "ctrl+p":
+		// Toggle between one theme and a light/dark pair
+		m.themeValue.Paired = !m.themeValue.Paired
+		if !m.themeValue.Paired {
+			m.themeValue.Dark = m.themeValue.Light
+		}
+		m.selectThemeSlot("light")
+
+	case "tab":
+		if m.themeValue.Paired {
+			if m.themeSlot == "light" {
+				m.selectThemeSlot("dark")
+			} else {
+				m.selectThemeSlot("light")
+			}
+		}
+
+	case "enter":
+		if m.themeCursor >= len(filtered) {
+			return m, nil
+		}
+		name := filtered[m.themeCursor].Name
+		switch {
+		case !m.themeValue.Paired:
+			m.themeValue.Light, m.themeValue.Dark = name, name
+		case m.themeSlot == "light":
+			// Fill the dark slot next
+			m.themeValue.Light = name
+			m.themeFilter = ""
+			m.selectThemeSlot("dark")
+			return m, nil
+		default:
+			m.themeValue.Dark = name
+		}
+		if m.themeValue.Light == "" {
+			return m, nil
+		}
+		m.commitValue(m.currentOption().Key, m.themeValue.String())
+		m.mode = m.returnMode
+		m.themeFilter = ""
+		return m, nil
+
+	case "backspace":
+		if len(m.themeFilter) > 0 {
+			m.themeFilter = m.themeFilter[:len(m.themeFilter)-1]
+			m.themeCursor = 0
+			m.themeOffset = 0
+		}
+
+	default:
+		// Add character to filter
+		if len(msg.String()) == 1 {
+			m.themeFilter += msg.String()
+			m.themeCursor = 0
+			m.themeOffset = 0
+		}
+	}
+
+	return m, nil
+}
+
 func (m Model) updateFontPicker(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	filteredFonts := m.getFilteredFonts()
 	maxVisible := m.height - 4
 
 	switch msg.String() {
 	case "esc":
-		m.mode = modeList
+		m.mode = m.returnMode
 		m.fontFilter = ""
 		return m, nil
 
@@ -449,13 +786,8 @@ func (m Model) updateFontPicker(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 			opt := m.sections[item.SectionIndex].Options[item.OptionIndex]
 			newValue := filteredFonts[m.fontCursor]
 
-			m.config.Set(opt.Key, newValue)
-			if err := m.config.Save(); err != nil {
-				m.message = fmt.Sprintf(i18n.T("msg.error"), err)
-			} else {
-				m.message = fmt.Sprintf(i18n.T("msg.saved"), opt.Key, newValue)
-			}
-			m.mode = modeList
+			m.commitValue(opt.Key, newValue)
+			m.mode = m.returnMode
 			m.fontFilter = ""
 			return m, nil
 		}
@@ -479,6 +811,277 @@ func (m Model) updateFontPicker(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	return m, nil
 }
 
+func (m Model) updateChoicePicker(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "esc":
+		m.mode = m.returnMode
+		return m, nil
+
+	case "up", "k":
+		if m.choiceCursor > 0 {
+			m.choiceCursor--
+		}
+
+	case "down", "j":
+		if m.choiceCursor < len(m.choices)-1 {
+			m.choiceCursor++
+		}
+
+	case "enter":
+		if m.choiceCursor < len(m.choices) {
+			m.commitValue(m.currentOption().Key, m.choices[m.choiceCursor])
+		}
+		m.mode = m.returnMode
+		return m, nil
+	}
+
+	return m, nil
+}
+
+func (m Model) updateValues(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	opt := m.currentOption()
+	values := m.config.GetAll(opt.Key)
+
+	switch msg.String() {
+	case "esc", "q":
+		m.mode = modeList
+		return m, nil
+
+	case "up", "k":
+		if m.valueCursor > 0 {
+			m.valueCursor--
+		}
+
+	case "down", "j":
+		if m.valueCursor < len(values)-1 {
+			m.valueCursor++
+		}
+
+	case "K", "shift+up":
+		if m.valueCursor > 0 && m.valueCursor < len(values) {
+			m.moveValue(opt.Key, m.valueCursor-1)
+		}
+
+	case "J", "shift+down":
+		if m.valueCursor < len(values)-1 {
+			m.moveValue(opt.Key, m.valueCursor+1)
+		}
+
+	case "d", "delete":
+		if m.valueCursor < len(values) {
+			removed := values[m.valueCursor]
+			if err := m.config.RemoveAt(opt.Key, m.valueCursor); err != nil {
+				m.message = fmt.Sprintf(i18n.T("msg.error"), err)
+				return m, nil
+			}
+			m.save(fmt.Sprintf(i18n.T("msg.removed"), opt.Key, removed))
+			m.clampValueCursor(opt.Key)
+		}
+
+	case "a":
+		return m.editValue(opt, len(values))
+
+	case "u":
+		m.undo()
+		m.clampValueCursor(opt.Key)
+
+	case "ctrl+r":
+		m.redo()
+		m.clampValueCursor(opt.Key)
+
+	case "enter", "e":
+		if m.valueCursor < len(values) {
+			return m.editValue(opt, m.valueCursor)
+		}
+	}
+
+	return m, nil
+}
+
+// clampValueCursor keeps the value cursor inside the values of key
+func (m *Model) clampValueCursor(key string) {
+	if n := len(m.config.GetAll(key)); m.valueCursor >= n {
+		m.valueCursor = max(n-1, 0)
+	}
+}
+
+// undo reverts the last edit and saves the config
+func (m *Model) undo() {
+	key, ok := m.config.Undo()
+	if !ok {
+		m.message = i18n.T("msg.nothing_to_undo")
+		return
+	}
+	m.save(fmt.Sprintf(i18n.T("msg.undone"), key))
+}
+
+// redo reapplies the last undone edit and saves the config
+func (m *Model) redo() {
+	key, ok := m.config.Redo()
+	if !ok {
+		m.message = i18n.T("msg.nothing_to_redo")
+		return
+	}
+	m.save(fmt.Sprintf(i18n.T("msg.redone"), key))
+}
+
+// save writes the config and shows success, or what went wrong.
+// Conflicts with changes made on disk are listed by viewConflicts
+// until they are resolved.
+func (m *Model) save(success string) {
+	err := m.config.Save()
+	var conflictErr *config.ConflictError
+	switch {
+	case errors.As(err, &conflictErr):
+		m.message = fmt.Sprintf(i18n.T("msg.conflicts"), len(conflictErr.Conflicts))
+		m.conflictCursor = 0
+	case err != nil:
+		m.message = fmt.Sprintf(i18n.T("msg.error"), err)
+	default:
+		m.message = success
+	}
+	m.problems = m.config.Diagnose(m.options, m.defaultKeybinds)
+	m.problemIndex = 0
+}
+
+// valid has Ghostty check the edit of key made since the undo depth was
+// depth (see config.ValidateEdit) before it is saved. A rejected edit is
+// reverted and the reason shown. If Ghostty cannot be run, the edit is
+// let through unchecked.
+func (m *Model) valid(key string, depth int) bool {
+	problems, err := m.config.ValidateEdit(key, depth)
+	if err != nil || len(problems) == 0 {
+		return true
+	}
+	m.config.Revert()
+	m.message = fmt.Sprintf(i18n.T("msg.invalid"), problems[0])
+	return false
+}
+
+// moveValue moves the value under the value cursor to index to and saves
+func (m *Model) moveValue(key string, to int) {
+	if err := m.config.Move(key, m.valueCursor, to); err != nil {
+		m.message = fmt.Sprintf(i18n.T("msg.error"), err)
+		return
+	}
+	// Saving can merge external changes that leave fewer values, so the
+	// moved value is read before and the cursor checked after
+	moved := m.config.GetAll(key)[to]
+	m.valueCursor = to
+	m.save(fmt.Sprintf(i18n.T("msg.moved"), key, moved))
+	m.clampValueCursor(key)
+}
+
+// editValue opens an editor for the value at index of a repeatable key.
+// An index past the last value appends a new one.
+func (m Model) editValue(opt schema.Option, index int) (tea.Model, tea.Cmd) {
+	m.valueIndex = index
+	m.returnMode = modeValues
+
+	var currentVal string
+	if values := m.config.GetAll(opt.Key); index < len(values) {
+		currentVal = values[index]
+	}
+
+	switch opt.Type() {
+	case schema.TypeFont:
+		return m.openFontPicker(currentVal)
+	case schema.TypeBool, schema.TypeEnum:
+		return m.openChoicePicker(opt, currentVal)
+	case schema.TypeKeybind:
+		return m.openKeybindEditor(currentVal)
+	}
+
+	m.mode = modeEdit
+	m.textInput.SetValue(currentVal)
+	m.textInput.Focus()
+	return m, textinput.Blink
+}
+
+// openKeybindEditor splits a keybind into the trigger and action fields.
+// A value that cannot be parsed is split at its first "=".
+func (m Model) openKeybindEditor(currentVal string) (tea.Model, tea.Cmd) {
+	trigger, action, _ := strings.Cut(currentVal, "=")
+	if kb, err := schema.ParseKeybind(currentVal); err == nil && len(kb.Sequence) > 0 {
+		trigger = strings.Join(append(slices.Clone(kb.Prefixes), kb.Trigger()), ":")
+		action = kb.Action
+		if kb.Param != "" {
+			action += ":" + kb.Param
+		}
+	}
+	m.loadActions()
+	m.keybindInputs[0].SetValue(trigger)
+	m.keybindInputs[1].SetValue(action)
+	// SetValue keeps the suggestions matched by the last value
+	m.keybindInputs[1].SetSuggestions(m.keybindInputs[1].AvailableSuggestions())
+	m.mode = modeKeybindEdit
+	return m, m.focusKeybindField(0)
+}
+
+// loadActions lists the actions for the action field once. They only
+// help with editing, so failing to list them is not an error.
+func (m *Model) loadActions() {
+	if m.actions != nil {
+		return
+	}
+	m.actions, _ = schema.ListActions()
+	names := make([]string, len(m.actions))
+	for i, a := range m.actions {
+		names[i] = a.Name
+	}
+	m.keybindInputs[1].SetSuggestions(names)
+	m.keybindInputs[1].ShowSuggestions = true
+}
+
+// focusKeybindField moves the cursor to the trigger (0) or action (1) field
+func (m *Model) focusKeybindField(field int) tea.Cmd {
+	m.keybindField = field
+	m.keybindInputs[1-field].Blur()
+	m.keybindInputs[field].Focus()
+	return textinput.Blink
+}
+
+func (m Model) updateKeybindEdit(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "esc":
+		m.mode = m.returnMode
+		m.keybindInputs[m.keybindField].Blur()
+		return m, nil
+
+	case "tab", "shift+tab", "up", "down":
+		// tab completes the action first
+		input := m.keybindInputs[1]
+		if msg.String() == "tab" && m.keybindField == 1 && input.CurrentSuggestion() != "" && input.CurrentSuggestion() != input.Value() {
+			m.keybindInputs[1], _ = input.Update(msg)
+			return m, nil
+		}
+		return m, m.focusKeybindField(1 - m.keybindField)
+
+	case "enter":
+		if m.keybindField == 0 {
+			return m, m.focusKeybindField(1)
+		}
+		// "clear" has no action
+		value := strings.TrimSpace(m.keybindInputs[0].Value())
+		if action := strings.TrimSpace(m.keybindInputs[1].Value()); action != "" {
+			value += "=" + action
+		}
+		kb, err := schema.ParseKeybind(value)
+		if err != nil {
+			m.message = fmt.Sprintf(i18n.T("msg.bad_value"), err)
+			return m, nil
+		}
+		m.commitValue(m.currentOption().Key, kb.String())
+		m.mode = m.returnMode
+		m.keybindInputs[m.keybindField].Blur()
+		return m, nil
+	}
+
+	var cmd tea.Cmd
+	m.keybindInputs[m.keybindField], cmd = m.keybindInputs[m.keybindField].Update(msg)
+	return m, cmd
+}
+
 func (m Model) getFilteredFonts() []string {
 	if m.fontFilter == "" {
 		return m.fonts
@@ -494,30 +1097,43 @@ func (m Model) getFilteredFonts() []string {
 	return filtered
 }
 
-func (m Model) View() string {
-	var b strings.Builder
+func (m Model) getFilteredThemes() []schema.Theme {
+	if m.themeFilter == "" {
+		return m.themes
+	}
 
-	b.WriteString(titleStyle.Render(i18n.T("app.title")))
-	b.WriteString("\n")
-	b.WriteString(pathStyle.Render(m.config.Path))
-	b.WriteString("\n")
+	filter := strings.ToLower(m.themeFilter)
+	var filtered []schema.Theme
+	for _, t := range m.themes {
+		if strings.Contains(strings.ToLower(t.Name), filter) {
+			filtered = append(filtered, t)
+		}
+	}
+	return filtered
+}
+
+func (m Model) View() string {
+	if len(m.config.Conflicts()) > 0 {
+		return m.viewConflicts()
+	}
 
 	switch m.mode {
 	case modeColorPicker:
 		return m.viewColorPicker()
+	case modeThemePicker:
+		return m.viewThemePicker()
 	case modeFontPicker:
 		return m.viewFontPicker()
+	case modeChoicePicker:
+		return m.viewChoicePicker()
+	case modeValues:
+		return m.viewValues()
+	case modeKeybindEdit:
+		return m.viewKeybindEdit()
 	}
 
-	if m.mode == modeSearch {
-		b.WriteString(i18n.T("tui.search"))
-		b.WriteString(m.textInput.View())
-		b.WriteString("\n\n")
-	} else if m.searchQuery != "" {
-		b.WriteString(fmt.Sprintf(i18n.T("tui.filter")+"\n\n", m.searchQuery))
-	} else {
-		b.WriteString("\n")
-	}
+	var b strings.Builder
+	b.WriteString(m.viewHeader())
 
 	// List items
 	end := m.offset + m.height
@@ -547,7 +1163,11 @@ func (m Model) View() string {
 		} else {
 			opt := m.sections[item.SectionIndex].Options[item.OptionIndex]
 			currentVal := m.config.Get(opt.Key)
-			optType := schema.GetOptionType(opt.Key)
+			optType := opt.Type()
+			if values := m.config.GetAll(opt.Key); config.IsRepeatable(opt.Key) && len(values) > 1 {
+				currentVal = fmt.Sprintf(i18n.T("tui.values_count"), len(values))
+				optType = schema.TypeText
+			}
 
 			var line string
 			val := currentVal
@@ -555,6 +1175,8 @@ func (m Model) View() string {
 				val = opt.DefaultValue
 			}
 
+			meta := opt.Metadata()
+
 			// Add color swatch for color options
 			var colorSwatch string
 			if optType == schema.TypeColor && val != "" {
@@ -571,20 +1193,66 @@ func (m Model) View() string {
 					line = colorSwatch + line
 				}
 			} else {
+				// Options for other platforms are greyed out
+				key := keyStyle.Render(opt.Key)
+				if !meta.Supports(schema.CurrentPlatform()) {
+					key = defaultStyle.Render(opt.Key)
+				}
 				if currentVal != "" {
-					line = fmt.Sprintf("    %s = %s", keyStyle.Render(opt.Key), valueStyle.Render(currentVal))
+					line = fmt.Sprintf("    %s = %s", key, valueStyle.Render(currentVal))
 				} else {
-					line = fmt.Sprintf("    %s = %s", keyStyle.Render(opt.Key), defaultStyle.Render(val+" "+i18n.T("tui.default")))
+					line = fmt.Sprintf("    %s = %s", key, defaultStyle.Render(val+" "+i18n.T("tui.default")))
 				}
 				if colorSwatch != "" {
 					line = colorSwatch + line
 				}
 			}
+			if meta.Deprecated != "" {
+				line += " " + problemStyle(config.SeverityWarning).Render(i18n.T("tui.deprecated_tag"))
+			}
 			b.WriteString(line)
 		}
 		b.WriteString("\n")
 	}
 
+	b.WriteString(m.viewFooter())
+	return b.String()
+}
+
+// viewHeader renders what the list view shows above the list
+func (m Model) viewHeader() string {
+	var b strings.Builder
+
+	b.WriteString(titleStyle.Render(i18n.T("app.title")))
+	b.WriteString("\n")
+	b.WriteString(pathStyle.Render(m.config.Path))
+	b.WriteString("\n")
+	// Options from the cache or the built-in snapshot may not match
+	// the Ghostty the config is used with
+	if m.schema.Origin == schema.OriginGhostty {
+		b.WriteString(countStyle.Render(m.schema.Describe()))
+	} else {
+		b.WriteString(problemStyle(config.SeverityWarning).Render(m.schema.Describe()))
+	}
+	b.WriteString("\n")
+
+	if m.mode == modeSearch {
+		b.WriteString(i18n.T("tui.search"))
+		b.WriteString(m.textInput.View())
+		b.WriteString("\n\n")
+	} else if m.searchQuery != "" {
+		b.WriteString(fmt.Sprintf(i18n.T("tui.filter")+"\n\n", m.searchQuery))
+	} else {
+		b.WriteString("\n")
+	}
+
+	return b.String()
+}
+
+// viewFooter renders what the list view shows below the list
+func (m Model) viewFooter() string {
+	var b strings.Builder
+
 	// Show description for selected option
 	if len(m.items) > 0 && m.cursor < len(m.items) {
 		item := m.items[m.cursor]
@@ -596,6 +1264,14 @@ func (m Model) View() string {
 			}
 			b.WriteString(descStyle.Render(desc))
 			b.WriteString("\n")
+			for _, note := range metaNotes(opt.Metadata()) {
+				b.WriteString(descStyle.Render(note))
+				b.WriteString("\n")
+			}
+			if src, ok := m.config.Source(opt.Key); ok {
+				b.WriteString(descStyle.Render(pathStyle.Render(fmt.Sprintf(i18n.T("tui.defined_in"), src))))
+				b.WriteString("\n")
+			}
 		}
 	}
 
@@ -604,6 +1280,10 @@ func (m Model) View() string {
 		b.WriteString("\n")
 		b.WriteString(i18n.T("tui.new_value"))
 		b.WriteString(m.textInput.View())
+		switch t := m.currentOption().Type(); t {
+		case schema.TypeNumber, schema.TypeInteger, schema.TypeDuration, schema.TypePath:
+			b.WriteString(" " + countStyle.Render("("+i18n.T("type."+t.String())+")"))
+		}
 		b.WriteString("\n")
 	}
 
@@ -613,6 +1293,13 @@ func (m Model) View() string {
 		b.WriteString(messageStyle.Render(m.message))
 	}
 
+	// Problems in the config files
+	if len(m.problems) > 0 {
+		p := m.problems[m.problemIndex]
+		b.WriteString("\n")
+		b.WriteString(problemStyle(p.Severity).Render(fmt.Sprintf(i18n.T("tui.problems"), m.problemIndex+1, len(m.problems), p)))
+	}
+
 	// Help
 	help := i18n.T("help.main")
 	if m.mode == modeEdit {
@@ -625,6 +1312,25 @@ func (m Model) View() string {
 	return b.String()
 }
 
+// metaNotes describes the platforms, version and deprecation of an option
+func metaNotes(meta schema.Metadata) []string {
+	var notes []string
+	if meta.Deprecated != "" {
+		notes = append(notes, problemStyle(config.SeverityWarning).Render(fmt.Sprintf(i18n.T("tui.deprecated"), meta.Deprecated)))
+	}
+	if !meta.Supports(schema.CurrentPlatform()) {
+		var names []string
+		for _, p := range meta.Platforms {
+			names = append(names, i18n.T("platform."+p))
+		}
+		notes = append(notes, defaultStyle.Render(fmt.Sprintf(i18n.T("tui.platform_only"), strings.Join(names, ", "))))
+	}
+	if meta.Since != "" {
+		notes = append(notes, defaultStyle.Render(fmt.Sprintf(i18n.T("tui.since"), meta.Since)))
+	}
+	return notes
+}
+
 func (m Model) viewColorPicker() string {
 	var b strings.Builder
 
@@ -676,6 +1382,90 @@ func (m Model) viewColorPicker() string {
 	return b.String()
 }
 
+func (m Model) viewThemePicker() string {
+	var b strings.Builder
+
+	opt := m.currentOption()
+	b.WriteString(titleStyle.Render(fmt.Sprintf(i18n.T("tui.select_theme"), opt.Key)))
+	b.WriteString("\n")
+
+	if currentVal := m.config.Get(opt.Key); currentVal != "" {
+		b.WriteString(fmt.Sprintf("Current: %s\n", valueStyle.Render(currentVal)))
+	}
+	if m.themeValue.Paired {
+		for _, slot := range []string{"light", "dark"} {
+			name := m.themeValue.Light
+			if slot == "dark" {
+				name = m.themeValue.Dark
+			}
+			line := fmt.Sprintf("%s: %s", i18n.T("tui.theme_"+slot), cmp.Or(name, "-"))
+			if slot == m.themeSlot {
+				b.WriteString(pickerSelectedStyle.Render("> "+line) + "  ")
+			} else {
+				b.WriteString("  " + pickerItemStyle.Render(line) + "  ")
+			}
+		}
+		b.WriteString("\n")
+	}
+	if m.themeFilter != "" {
+		b.WriteString(fmt.Sprintf("Filter: %s\n", m.themeFilter))
+	}
+	b.WriteString("\n")
+
+	filtered := m.getFilteredThemes()
+	end := min(m.themeOffset+m.themePickerHeight(), len(filtered))
+	width := 0
+	for _, t := range filtered[m.themeOffset:end] {
+		width = max(width, lipgloss.Width(t.Name))
+	}
+	for i := m.themeOffset; i < end; i++ {
+		t := filtered[i]
+		name := t.Name + strings.Repeat(" ", width-lipgloss.Width(t.Name))
+		if i == m.themeCursor {
+			b.WriteString(pickerSelectedStyle.Render("> " + name))
+		} else {
+			b.WriteString("  " + pickerItemStyle.Render(name))
+		}
+		b.WriteString(" " + themeSwatches(t) + "\n")
+	}
+
+	if len(filtered) == 0 {
+		b.WriteString(defaultStyle.Render("  " + i18n.T("tui.no_themes") + "\n"))
+	} else if m.themeCursor < len(filtered) {
+		b.WriteString("\n" + themePreview(filtered[m.themeCursor]))
+	}
+
+	b.WriteString(helpStyle.Render(fmt.Sprintf("\n"+i18n.T("help.theme"), len(filtered))))
+
+	return b.String()
+}
+
+// themeSwatches draws the 16 palette colors of a theme
+func themeSwatches(t schema.Theme) string {
+	var b strings.Builder
+	for _, c := range t.Palette {
+		b.WriteString(lipgloss.NewStyle().Background(lipgloss.Color(c)).Render(" "))
+	}
+	return b.String()
+}
+
+// themePreview draws a sample prompt in the colors of a theme
+func themePreview(t schema.Theme) string {
+	base := lipgloss.NewStyle().Background(lipgloss.Color(t.Background)).Foreground(lipgloss.Color(t.Foreground))
+	fg := func(index int, s string) string {
+		if index >= len(t.Palette) || t.Palette[index] == "" {
+			return base.Render(s)
+		}
+		return base.Foreground(lipgloss.Color(t.Palette[index])).Render(s)
+	}
+	cursor := lipgloss.NewStyle().Background(lipgloss.Color(cmp.Or(t.Cursor, t.Foreground))).Render(" ")
+	lines := []string{
+		base.Render(" ") + fg(2, "user@ghostty") + base.Render(":") + fg(4, "~/src") + base.Render("$ ls ") + cursor + base.Render("        "),
+		base.Render(" ") + fg(1, "error") + base.Render(" ") + fg(3, "warning") + base.Render(" ") + fg(5, "info") + base.Render(" ") + fg(6, "done") + base.Render("         "),
+	}
+	return "  " + lines[0] + "\n  " + lines[1] + "\n"
+}
+
 func (m Model) viewFontPicker() string {
 	var b strings.Builder
 
@@ -724,3 +1514,263 @@ func (m Model) viewFontPicker() string {
 
 	return b.String()
 }
+
+func (m Model) viewChoicePicker() string {
+	var b strings.Builder
+
+	opt := m.currentOption()
+	b.WriteString(titleStyle.Render(fmt.Sprintf(i18n.T("tui.select_value"), opt.Key)))
+	b.WriteString("\n\n")
+
+	for i, choice := range m.choices {
+		line := choice
+		if choice == opt.DefaultValue {
+			line += " " + i18n.T("tui.default")
+		}
+		if i == m.choiceCursor {
+			b.WriteString(pickerSelectedStyle.Render("> " + line))
+		} else {
+			b.WriteString("  " + pickerItemStyle.Render(line))
+		}
+		b.WriteString("\n")
+	}
+
+	b.WriteString(helpStyle.Render("\n" + i18n.T("help.choice")))
+
+	return b.String()
+}
+
+func (m Model) viewValues() string {
+	var b strings.Builder
+
+	opt := m.currentOption()
+	values := m.config.GetAll(opt.Key)
+
+	b.WriteString(titleStyle.Render(fmt.Sprintf(i18n.T("tui.values"), opt.Key)))
+	b.WriteString("\n\n")
+
+	sources := m.config.Sources[opt.Key]
+	lines := values
+	if opt.Type() == schema.TypeKeybind {
+		lines = keybindTable(values, m.defaultKeybinds)
+		b.WriteString("      " + defaultStyle.Render(lines[0]) + "\n")
+		lines = lines[1:]
+	}
+	for i := range values {
+		line := fmt.Sprintf("%2d. %s", i+1, lines[i])
+		if i < len(sources) && sources[i].File != m.config.Path {
+			line += "  " + pathStyle.Render(sources[i].String())
+		}
+		if i == m.valueCursor {
+			b.WriteString(pickerSelectedStyle.Render("> " + line))
+		} else {
+			b.WriteString("  " + pickerItemStyle.Render(line))
+		}
+		b.WriteString("\n")
+	}
+
+	if len(values) == 0 {
+		b.WriteString(defaultStyle.Render("  " + i18n.T("tui.no_values") + "\n"))
+	}
+
+	if m.message != "" {
+		b.WriteString("\n")
+		b.WriteString(messageStyle.Render(m.message))
+	}
+
+	b.WriteString(helpStyle.Render("\n" + i18n.T("help.values")))
+
+	return b.String()
+}
+
+// keybindTable lays out keybind values as rows of prefixes, keys, action
+// and parameter under a header row, noting the bindings and defaults each
+// replaces (see schema.KeybindConflicts). Values that cannot be parsed are
+// shown as written.
+func keybindTable(values []string, defaults []schema.Keybind) []string {
+	rows := [][4]string{{
+		i18n.T("tui.keybind_prefixes"), i18n.T("tui.keybind_trigger"),
+		i18n.T("tui.keybind_action"), i18n.T("tui.keybind_param"),
+	}}
+	valid := []bool{true}
+	var binds []schema.Keybind
+	var bindRows []int
+	for _, v := range values {
+		kb, err := schema.ParseKeybind(v)
+		if err != nil {
+			rows = append(rows, [4]string{v})
+			valid = append(valid, false)
+			continue
+		}
+		rows = append(rows, [4]string{strings.Join(kb.Prefixes, ","), kb.Trigger(), kb.Action, kb.Param})
+		valid = append(valid, true)
+		binds = append(binds, kb)
+		bindRows = append(bindRows, len(rows)-1)
+	}
+
+	notes := make([][]string, len(rows))
+	for _, c := range schema.KeybindConflicts(binds, defaults) {
+		var note string
+		style := problemStyle(config.SeverityWarning)
+		switch {
+		case c.Kind == schema.ConflictShadowed:
+			note = fmt.Sprintf(i18n.T("tui.keybind_shadows"), c.Default)
+			style = problemStyle(config.SeverityInfo)
+		case c.Other < 0:
+			note = fmt.Sprintf(i18n.T("tui.keybind_prefix_default"), c.Default)
+		case c.Kind == schema.ConflictDuplicate:
+			note = fmt.Sprintf(i18n.T("tui.keybind_duplicate"), bindRows[c.Other])
+		default:
+			note = fmt.Sprintf(i18n.T("tui.keybind_prefix"), bindRows[c.Other])
+		}
+		row := bindRows[c.Index]
+		notes[row] = append(notes[row], style.Render(note))
+	}
+
+	var widths [3]int
+	for i, row := range rows {
+		for col := range widths {
+			if valid[i] {
+				widths[col] = max(widths[col], lipgloss.Width(row[col]))
+			}
+		}
+	}
+	lines := make([]string, len(rows))
+	for i, row := range rows {
+		if !valid[i] {
+			lines[i] = row[0] + " " + problemStyle(config.SeverityError).Render(i18n.T("tui.keybind_invalid"))
+			continue
+		}
+		var b strings.Builder
+		for col, w := range widths {
+			b.WriteString(row[col] + strings.Repeat(" ", w-lipgloss.Width(row[col])+2))
+		}
+		lines[i] = strings.Join(append([]string{b.String() + row[3]}, notes[i]...), "  ")
+	}
+	return lines
+}
+
+// shadowNote says which defaults kb replaces, or is empty
+func shadowNote(kb schema.Keybind, defaults []schema.Keybind) string {
+	shadowed := kb.Shadows(defaults)
+	if len(shadowed) == 0 {
+		return ""
+	}
+	names := make([]string, len(shadowed))
+	for i, d := range shadowed {
+		names[i] = d.String()
+	}
+	return fmt.Sprintf(i18n.T("tui.keybind_shadows"), strings.Join(names, ", "))
+}
+
+// keybindAction returns the action named in the action field, or the
+// one it would be completed to
+func (m Model) keybindAction() (schema.Action, bool) {
+	input := m.keybindInputs[1]
+	name, _, _ := strings.Cut(strings.TrimSpace(input.Value()), ":")
+	if m.keybindField == 1 && input.CurrentSuggestion() != "" {
+		name = input.CurrentSuggestion()
+	}
+	i := slices.IndexFunc(m.actions, func(a schema.Action) bool { return a.Name == name })
+	if i < 0 {
+		return schema.Action{}, false
+	}
+	return m.actions[i], true
+}
+
+func (m Model) viewKeybindEdit() string {
+	var b strings.Builder
+
+	opt := m.currentOption()
+	b.WriteString(titleStyle.Render(fmt.Sprintf(i18n.T("tui.values"), opt.Key)))
+	b.WriteString("\n\n")
+	b.WriteString(i18n.T("tui.keybind_trigger_input") + m.keybindInputs[0].View() + "\n")
+	b.WriteString(i18n.T("tui.keybind_action_input") + m.keybindInputs[1].View() + "\n")
+
+	if action, ok := m.keybindAction(); ok {
+		b.WriteString(descStyle.Render(action.Name + ": " + action.Summary()))
+		b.WriteString("\n")
+	}
+	value := strings.TrimSpace(m.keybindInputs[0].Value()) + "=" + strings.TrimSpace(m.keybindInputs[1].Value())
+	if kb, err := schema.ParseKeybind(value); err == nil {
+		if note := shadowNote(kb, m.defaultKeybinds); note != "" {
+			b.WriteString("\n" + problemStyle(config.SeverityWarning).Render(note) + "\n")
+		}
+	}
+
+	if m.message != "" {
+		b.WriteString("\n")
+		b.WriteString(messageStyle.Render(m.message))
+	}
+
+	b.WriteString(helpStyle.Render("\n" + i18n.T("help.keybind")))
+
+	return b.String()
+}
+
+func (m Model) updateConflicts(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	conflicts := m.config.Conflicts()
+	if m.conflictCursor >= len(conflicts) {
+		m.conflictCursor = len(conflicts) - 1
+	}
+
+	switch msg.String() {
+	case "ctrl+c":
+		return m, tea.Quit
+
+	case "up", "k":
+		if m.conflictCursor > 0 {
+			m.conflictCursor--
+		}
+
+	case "down", "j":
+		if m.conflictCursor < len(conflicts)-1 {
+			m.conflictCursor++
+		}
+
+	case "m", "t":
+		cf := conflicts[m.conflictCursor]
+		if err := m.config.Resolve(cf.File, cf.Key, msg.String() == "m"); err != nil {
+			m.message = fmt.Sprintf(i18n.T("msg.error"), err)
+			return m, nil
+		}
+		if len(m.config.Conflicts()) == 0 {
+			m.save(i18n.T("msg.conflicts_resolved"))
+		}
+	}
+
+	return m, nil
+}
+
+func (m Model) viewConflicts() string {
+	var b strings.Builder
+
+	b.WriteString(titleStyle.Render(i18n.T("tui.conflicts")))
+	b.WriteString("\n")
+	b.WriteString(descStyle.Render(i18n.T("tui.conflicts_desc")))
+	b.WriteString("\n\n")
+
+	for i, cf := range m.config.Conflicts() {
+		line := fmt.Sprintf("%s  %s", cf.Key, pathStyle.Render(cf.File))
+		if i == m.conflictCursor {
+			b.WriteString(pickerSelectedStyle.Render("> " + line))
+		} else {
+			b.WriteString("  " + pickerItemStyle.Render(line))
+		}
+		b.WriteString("\n")
+		if i == m.conflictCursor {
+			b.WriteString(fmt.Sprintf("    %s %s\n", keyStyle.Render(i18n.T("tui.conflict_mine")), valueStyle.Render(strings.Join(cf.Mine, ", "))))
+			b.WriteString(fmt.Sprintf("    %s %s\n", keyStyle.Render(i18n.T("tui.conflict_theirs")), valueStyle.Render(strings.Join(cf.Theirs, ", "))))
+			b.WriteString(fmt.Sprintf("    %s %s\n", keyStyle.Render(i18n.T("tui.conflict_base")), defaultStyle.Render(strings.Join(cf.Base, ", "))))
+		}
+	}
+
+	if m.message != "" {
+		b.WriteString("\n")
+		b.WriteString(messageStyle.Render(m.message))
+	}
+
+	b.WriteString(helpStyle.Render("\n" + i18n.T("help.conflicts")))
+
+	return b.String()
+}
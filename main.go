@@ -6,6 +6,7 @@ import (
 	"os"
 
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/otiai10/ghostconfig/internal/cli"
 	"github.com/otiai10/ghostconfig/internal/config"
 	"github.com/otiai10/ghostconfig/internal/gui"
 	"github.com/otiai10/ghostconfig/internal/i18n"
@@ -14,25 +15,34 @@ import (
 )
 
 func main() {
+	// Initialize i18n
+	i18n.Init()
+
+	// Subcommands (history, restore, ...) run without a UI
+	if code, ok := cli.Run(os.Args[1:]); ok {
+		os.Exit(code)
+	}
+
 	tuiMode := flag.Bool("tui", false, "Use TUI mode (terminal interface)")
 	guiMode := flag.Bool("gui", false, "Use GUI mode (web browser interface)")
 	port := flag.Int("port", 9999, "Port for GUI server")
 	configFile := flag.String("file", "", "Path to config file (default: ~/.config/ghostty/config)")
+	backupDir := flag.String("backup-dir", "", "Directory for config snapshots (default: $XDG_STATE_HOME/ghostconfig/backups)")
+	backups := flag.Int("backups", config.DefaultBackupKeep, "Number of snapshots to keep per config file (0 disables backups)")
+	refreshSchema := flag.Bool("refresh-schema", false, "Parse the Ghostty schema and font list again instead of using the cache")
 	flag.Parse()
 
-	// Initialize i18n
-	i18n.Init()
-
-	// Parse Ghostty schema
-	options, err := schema.Parse()
+	// Load Ghostty schema, cached per Ghostty build; without Ghostty,
+	// the cached or built-in schema is used
+	sch, err := schema.Load(*refreshSchema)
 	if err != nil {
 		fmt.Fprintf(os.Stderr, i18n.T("error.parse_schema")+"\n", err)
-		fmt.Fprintf(os.Stderr, i18n.T("error.ghostty_not_found")+"\n")
+		fmt.Fprintln(os.Stderr, i18n.T("error.ghostty_not_found"))
 		os.Exit(1)
 	}
 
-	if len(options) == 0 {
-		fmt.Fprintf(os.Stderr, i18n.T("error.no_options")+"\n")
+	if len(sch.Options) == 0 {
+		fmt.Fprintln(os.Stderr, i18n.T("error.no_options"))
 		os.Exit(1)
 	}
 
@@ -42,17 +52,20 @@ func main() {
 		fmt.Fprintf(os.Stderr, i18n.T("error.load_config")+"\n", err)
 		os.Exit(1)
 	}
+	if *backups > 0 {
+		cfg.Backups = config.NewBackupStore(*backupDir, *backups)
+	}
 
 	// Mode selection: --tui uses TUI, otherwise GUI (default)
 	if *tuiMode && !*guiMode {
-		runTUI(options, cfg)
+		runTUI(sch, cfg)
 	} else {
-		runGUI(options, cfg, *port)
+		runGUI(sch, cfg, *port)
 	}
 }
 
-func runTUI(options []schema.Option, cfg *config.Config) {
-	model := tui.New(options, cfg)
+func runTUI(sch schema.Schema, cfg *config.Config) {
+	model := tui.New(sch, cfg)
 	p := tea.NewProgram(model, tea.WithAltScreen())
 
 	if _, err := p.Run(); err != nil {
@@ -61,8 +74,8 @@ func runTUI(options []schema.Option, cfg *config.Config) {
 	}
 }
 
-func runGUI(options []schema.Option, cfg *config.Config, port int) {
-	server := gui.NewServer(options, cfg, port)
+func runGUI(sch schema.Schema, cfg *config.Config, port int) {
+	server := gui.NewServer(sch, cfg, port)
 	if err := server.Start(); err != nil {
 		fmt.Fprintf(os.Stderr, i18n.T("error.gui")+"\n", err)
 		os.Exit(1)